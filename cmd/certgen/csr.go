@@ -0,0 +1,62 @@
+package main
+
+import (
+	"path/filepath"
+
+	"github.com/jim-minter/certgen/pkg/certgen"
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+func runCSR(args []string) error {
+	flags := newFlagSet("csr", "-config FILE [flags]", `Write a private key and certificate signing request for each CA of the
+cluster described by FILE which is not already listed under "cas", so that the
+CAs can be signed as intermediates by an offline root CA.  Once signed, list
+each certificate and its key under "cas" and the root certificate under
+"rootCA", then run "certgen generate".`)
+	configFile := flags.String("config", "", "cluster description `file` (YAML or JSON)")
+	path := flags.String("path", "", "output `directory` (default <output path>/ca-csrs)")
+	force := flags.Bool("force", false, "replace an output directory not written by certgen")
+	err := parseFlags(flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return usageErrorf(flags, "unexpected argument %q", flags.Arg(0))
+	}
+	if *configFile == "" {
+		return usageErrorf(flags, "-config must be specified")
+	}
+
+	d, err := certgen.LoadClusterDescription(*configFile)
+	if err != nil {
+		return err
+	}
+
+	c, err := d.Config()
+	if err != nil {
+		return err
+	}
+
+	err = c.PrepareCACSRs()
+	if err != nil {
+		return err
+	}
+
+	if *path == "" {
+		*path = filepath.Join(d.OutputPath(), "ca-csrs")
+	}
+
+	fs, err := filesystem.NewFilesystem(*path, filesystem.DirectoryOptions{Force: *force})
+	if err != nil {
+		return err
+	}
+
+	err = c.WriteCACSRs(fs)
+	if err != nil {
+		filesystem.Abort(fs)
+		return err
+	}
+
+	return fs.Close()
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/jim-minter/certgen/pkg/certgen"
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+func runDiff(args []string) error {
+	flags := newFlagSet("diff", "OLD NEW", `Compare OLD and NEW, directories or archives previously written by "certgen
+generate".  Files present in only one tree are listed; certificates and
+kubeconfigs are compared field by field and other files line by line.
+
+The exit status is 0 if the trees are the same, 1 if they differ and 2 on
+error.`)
+	err := parseFlags(flags, args, 2)
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 2 {
+		return usageErrorf(flags, "unexpected argument %q", flags.Arg(2))
+	}
+
+	oldr, err := filesystem.Open(flags.Arg(0))
+	if err != nil {
+		return diffError(err)
+	}
+
+	newr, err := filesystem.Open(flags.Arg(1))
+	if err != nil {
+		return diffError(err)
+	}
+
+	differ, err := diffTrees(os.Stdout, oldr, newr)
+	if err != nil {
+		return diffError(err)
+	}
+
+	if differ {
+		return exitError(1)
+	}
+
+	return nil
+}
+
+// diffError reports err and returns exit status 2, as diff(1) does.
+func diffError(err error) error {
+	fmt.Fprintf(os.Stderr, "certgen diff: %v\n", err)
+	return exitError(2)
+}
+
+func diffTrees(w io.Writer, oldr, newr filesystem.Reader) (bool, error) {
+	oldfiles, err := oldr.Files()
+	if err != nil {
+		return false, err
+	}
+
+	newfiles, err := newr.Files()
+	if err != nil {
+		return false, err
+	}
+
+	var differ bool
+	for len(oldfiles) > 0 || len(newfiles) > 0 {
+		switch {
+		case len(newfiles) == 0 || len(oldfiles) > 0 && oldfiles[0] < newfiles[0]:
+			fmt.Fprintf(w, "only in old: %s\n", oldfiles[0])
+			oldfiles = oldfiles[1:]
+			differ = true
+
+		case len(oldfiles) == 0 || newfiles[0] < oldfiles[0]:
+			fmt.Fprintf(w, "only in new: %s\n", newfiles[0])
+			newfiles = newfiles[1:]
+			differ = true
+
+		default:
+			filename := oldfiles[0]
+			oldfiles, newfiles = oldfiles[1:], newfiles[1:]
+
+			oldb, err := oldr.ReadFile(filename)
+			if err != nil {
+				return false, err
+			}
+
+			newb, err := newr.ReadFile(filename)
+			if err != nil {
+				return false, err
+			}
+
+			if bytes.Equal(oldb, newb) {
+				continue
+			}
+			differ = true
+
+			fmt.Fprintf(w, "changed: %s\n", filename)
+			for _, line := range diffFile(filename, oldb, newb) {
+				fmt.Fprintf(w, "  %s\n", line)
+			}
+		}
+	}
+
+	return differ, nil
+}
+
+func diffFile(filename string, oldb, newb []byte) []string {
+	switch fileKind(filename) {
+	case kindCertificate:
+		oldcerts, olderr := certgen.ParseCertificates(oldb)
+		newcerts, newerr := certgen.ParseCertificates(newb)
+		if olderr == nil && newerr == nil {
+			return diffCertificates("", oldcerts, newcerts)
+		}
+
+	case kindKubeConfig:
+		oldkc, olderr := parseKubeConfig(oldb)
+		newkc, newerr := parseKubeConfig(newb)
+		if olderr == nil && newerr == nil {
+			o, n := kubeConfigData(oldkc), kubeConfigData(newkc)
+
+			var lines []string
+			if oldkc.CurrentContext != newkc.CurrentContext {
+				lines = append(lines, fmt.Sprintf("current context: %s -> %s", oldkc.CurrentContext, newkc.CurrentContext))
+			}
+			if o.server != n.server {
+				lines = append(lines, fmt.Sprintf("server: %s -> %s", o.server, n.server))
+			}
+
+			oldcas, _ := parseData(o.ca)
+			newcas, _ := parseData(n.ca)
+			lines = append(lines, diffCertificates("certificate authority ", oldcas, newcas)...)
+
+			oldcerts, _ := parseData(o.cert)
+			newcerts, _ := parseData(n.cert)
+			lines = append(lines, diffCertificates("client ", oldcerts, newcerts)...)
+
+			if o.key != n.key {
+				lines = append(lines, "client key changed")
+			}
+
+			return lines
+		}
+
+	case kindPrivateKey, kindPublicKey:
+		return []string{"key changed"}
+	}
+
+	return diffLines(string(oldb), string(newb))
+}
+
+type kubeConfigFields struct {
+	server, ca, cert, key string
+}
+
+// Generated kubeconfigs contain a single cluster and user.
+func kubeConfigData(kc *certgen.KubeConfig) (f kubeConfigFields) {
+	if len(kc.Clusters) > 0 {
+		f.server = kc.Clusters[0].Cluster.Server
+		f.ca = kc.Clusters[0].Cluster.CertificateAuthorityData
+	}
+	if len(kc.Users) > 0 {
+		f.cert = kc.Users[0].User.ClientCertificateData
+		f.key = kc.Users[0].User.ClientKeyData
+	}
+	return
+}
+
+func diffCertificates(prefix string, oldcerts, newcerts []*x509.Certificate) []string {
+	if len(oldcerts) != len(newcerts) {
+		return []string{fmt.Sprintf("%scertificate count: %d -> %d", prefix, len(oldcerts), len(newcerts))}
+	}
+
+	var lines []string
+	for i := range oldcerts {
+		o, n := oldcerts[i], newcerts[i]
+		if bytes.Equal(o.Raw, n.Raw) {
+			continue
+		}
+
+		p := prefix
+		if len(oldcerts) > 1 {
+			p = fmt.Sprintf("%scertificate %d ", prefix, i)
+		}
+
+		for _, field := range []struct {
+			name     string
+			old, new interface{}
+		}{
+			{"subject", o.Subject.String(), n.Subject.String()},
+			{"issuer", o.Issuer.String(), n.Issuer.String()},
+			{"validity", o.NotAfter.Sub(o.NotBefore).String(), n.NotAfter.Sub(n.NotBefore).String()},
+			{"public key", describePublicKey(o.PublicKey), describePublicKey(n.PublicKey)},
+			{"CA", o.IsCA, n.IsCA},
+			{"key usage", keyUsageNames(o.KeyUsage), keyUsageNames(n.KeyUsage)},
+			{"extended key usage", extKeyUsageNames(o.ExtKeyUsage), extKeyUsageNames(n.ExtKeyUsage)},
+			{"DNS names", o.DNSNames, n.DNSNames},
+			{"IP addresses", fmt.Sprint(o.IPAddresses), fmt.Sprint(n.IPAddresses)},
+		} {
+			if !reflect.DeepEqual(field.old, field.new) {
+				lines = append(lines, fmt.Sprintf("%s%s: %v -> %v", p, field.name, field.old, field.new))
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("%sreissued: serial %s -> %s", p, o.SerialNumber, n.SerialNumber))
+	}
+
+	return lines
+}
+
+// diffLines trims any common prefix and suffix before diffing a and b.
+func diffLines(a, b string) []string {
+	al, bl := strings.Split(a, "\n"), strings.Split(b, "\n")
+
+	for len(al) > 0 && len(bl) > 0 && al[0] == bl[0] {
+		al, bl = al[1:], bl[1:]
+	}
+	for len(al) > 0 && len(bl) > 0 && al[len(al)-1] == bl[len(bl)-1] {
+		al, bl = al[:len(al)-1], bl[:len(bl)-1]
+	}
+
+	// longest common subsequence of the remaining lines
+	lcs := make([][]int, len(al)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bl)+1)
+	}
+	for i := len(al) - 1; i >= 0; i-- {
+		for j := len(bl) - 1; j >= 0; j-- {
+			if al[i] == bl[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(al) || j < len(bl) {
+		switch {
+		case i < len(al) && j < len(bl) && al[i] == bl[j]:
+			i, j = i+1, j+1
+		case j == len(bl) || i < len(al) && lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "-"+al[i])
+			i++
+		default:
+			lines = append(lines, "+"+bl[j])
+			j++
+		}
+	}
+
+	return lines
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/certgen"
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+func runGenerate(args []string) error {
+	flags := newFlagSet("generate", "-config FILE [flags]", `Generate certificates, keys, kubeconfigs and configuration files for every
+node in the cluster described by FILE.  One directory or archive (a tgz, zip or
+cpio file, cloud-config document or Ignition config) is written per node.
+cloud-config documents cannot set the owners or modes of directories, so an
+ownership rule which would is refused.
+
+With -update, output already written for the cluster is read back first, and
+the CAs, keys, certificates and secrets it contains are kept where they are
+still valid, so that nodes can be added without disturbing the rest of the
+cluster.  Only new or missing artifacts are generated.`)
+	configFile := flags.String("config", "", "cluster description `file` (YAML or JSON)")
+	output := addOutputFlags(flags)
+	update := flags.Bool("update", false, "reuse valid artifacts from existing output")
+	err := parseFlags(flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return usageErrorf(flags, "unexpected argument %q", flags.Arg(0))
+	}
+	if *configFile == "" {
+		return usageErrorf(flags, "-config must be specified")
+	}
+
+	d, err := certgen.LoadClusterDescription(*configFile)
+	if err != nil {
+		return err
+	}
+
+	err = output.apply(d)
+	if err != nil {
+		return err
+	}
+
+	c, err := d.Config()
+	if err != nil {
+		return err
+	}
+
+	if *update {
+		err = loadExisting(d, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = prepare(c)
+	if err != nil {
+		return err
+	}
+
+	return write(d, c)
+}
+
+// outputFlags are the flags of the commands which write output, overriding the
+// cluster description's output.
+type outputFlags struct {
+	format  *string
+	path    *string
+	inPlace *bool
+	force   *bool
+}
+
+func addOutputFlags(flags *flag.FlagSet) *outputFlags {
+	return &outputFlags{
+		format:  flags.String("output", "", "output `format`: directory, tgz, zip, cpio, cloud-init or ignition (overrides the cluster description)"),
+		path:    flags.String("path", "", "output `directory` (overrides the cluster description)"),
+		inPlace: flags.Bool("in-place", false, "update output directories in place (overrides the cluster description)"),
+		force:   flags.Bool("force", false, "replace output directories not written by certgen"),
+	}
+}
+
+func (o *outputFlags) apply(d *certgen.ClusterDescription) error {
+	if *o.format != "" {
+		d.Output.Format = *o.format
+	}
+	if *o.path != "" {
+		d.Output.Path = *o.path
+	}
+	if *o.inPlace {
+		d.Output.InPlace = true
+	}
+	d.Output.Force = *o.force
+
+	return d.Validate()
+}
+
+func prepare(c *certgen.Config) error {
+	err := c.PrepareCAs()
+	if err != nil {
+		return err
+	}
+
+	err = c.PrepareComponentCerts()
+	if err != nil {
+		return err
+	}
+
+	err = c.PrepareNamedCertificate()
+	if err != nil {
+		return err
+	}
+
+	for i, node := range c.Nodes {
+		if node.Master == nil {
+			continue
+		}
+		err = c.PrepareMasterCerts(&c.Nodes[i])
+		if err != nil {
+			return err
+		}
+		err = c.PrepareMasterKeypair(&c.Nodes[i])
+		if err != nil {
+			return err
+		}
+		err = c.PrepareMasterKubeConfigs(&c.Nodes[i])
+		if err != nil {
+			return err
+		}
+		err = c.PrepareMasterFiles(&c.Nodes[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, member := range c.EtcdMembers() {
+		err = c.PrepareEtcdCerts(member)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range c.Nodes {
+		err := c.PrepareNodeCerts(&c.Nodes[i])
+		if err != nil {
+			return err
+		}
+
+		err = c.PrepareNodeKubeConfig(&c.Nodes[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Output directories are all checked first, so that none is written if any
+// would be refused.
+func write(d *certgen.ClusterDescription, c *certgen.Config) error {
+	if d.OutputFormat() == certgen.OutputFormatDirectory {
+		for _, host := range hosts(c) {
+			err := filesystem.CheckDirectory(outputName(d, host.Hostname), directoryOptions(d))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, node := range c.Nodes {
+		fs, err := newFilesystem(d, node.Hostname)
+		if err != nil {
+			return err
+		}
+
+		err = c.WriteNode(fs, &c.Nodes[i])
+		if err != nil {
+			filesystem.Abort(fs)
+			return err
+		}
+
+		err = fs.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, host := range c.EtcdHosts {
+		fs, err := newFilesystem(d, host.Hostname)
+		if err != nil {
+			return err
+		}
+
+		err = c.WriteEtcd(fs, &c.EtcdHosts[i])
+		if err != nil {
+			filesystem.Abort(fs)
+			return err
+		}
+
+		err = fs.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Masters are loaded first, as only they hold the CAs' keys.
+func loadExisting(d *certgen.ClusterDescription, c *certgen.Config) error {
+	for _, host := range hosts(c) {
+		name := outputName(d, host.Hostname)
+
+		r, err := filesystem.Open(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		err = c.Load(r, host)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// hosts returns every host in c, masters first.
+func hosts(c *certgen.Config) []*certgen.Node {
+	hosts := c.Masters()
+	for i := range c.Nodes {
+		if c.Nodes[i].Master == nil {
+			hosts = append(hosts, &c.Nodes[i])
+		}
+	}
+	for i := range c.EtcdHosts {
+		hosts = append(hosts, &c.EtcdHosts[i])
+	}
+	return hosts
+}
+
+var outputExtensions = map[string]string{
+	certgen.OutputFormatTGZ:       ".tgz",
+	certgen.OutputFormatZip:       ".zip",
+	certgen.OutputFormatCPIO:      ".cpio",
+	certgen.OutputFormatCloudInit: ".yaml",
+	certgen.OutputFormatIgnition:  ".ign",
+}
+
+func outputName(d *certgen.ClusterDescription, hostname string) string {
+	return filepath.Join(d.OutputPath(), hostname+outputExtensions[d.OutputFormat()])
+}
+
+func newFilesystem(d *certgen.ClusterDescription, hostname string) (filesystem.Filesystem, error) {
+	if d.OutputFormat() == certgen.OutputFormatDirectory {
+		fs, err := filesystem.NewFilesystem(outputName(d, hostname), directoryOptions(d))
+		if err != nil {
+			return nil, err
+		}
+
+		if d.OutputPolicy() != nil {
+			err = filesystem.SetPolicy(fs, d.OutputPolicy())
+			if err != nil {
+				filesystem.Abort(fs)
+				return nil, err
+			}
+		}
+		return fs, nil
+	}
+
+	var modTime time.Time
+	if d.Output.Reproducible {
+		var err error
+		modTime, err = reproducibleModTime()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := os.MkdirAll(d.OutputPath(), 0777)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := filesystem.CreateAtomic(outputName(d, hostname), 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	var fs filesystem.Filesystem
+	switch d.OutputFormat() {
+	case certgen.OutputFormatTGZ:
+		fs, err = filesystem.NewTGZFile(f)
+	case certgen.OutputFormatZip:
+		fs, err = filesystem.NewZipFile(f)
+	case certgen.OutputFormatCPIO:
+		fs, err = filesystem.NewCPIOFile(f)
+	case certgen.OutputFormatCloudInit:
+		fs, err = filesystem.NewCloudInitFile(f, d.Output.Gzip)
+	case certgen.OutputFormatIgnition:
+		fs, err = filesystem.NewIgnitionFile(f, d.IgnitionVersion())
+	default:
+		err = fmt.Errorf("unknown output format %q", d.OutputFormat())
+	}
+	if err == nil && d.OutputPolicy() != nil {
+		err = filesystem.SetPolicy(fs, d.OutputPolicy())
+	}
+	if err == nil && d.Output.Reproducible {
+		fs, err = filesystem.NewReproducible(fs, modTime)
+	}
+	if err != nil {
+		f.Abort()
+		return nil, err
+	}
+
+	return &fileCloser{Filesystem: fs, f: f}, nil
+}
+
+func directoryOptions(d *certgen.ClusterDescription) filesystem.DirectoryOptions {
+	return filesystem.DirectoryOptions{InPlace: d.Output.InPlace, Force: d.Output.Force}
+}
+
+// Reproducible output is stamped with $SOURCE_DATE_EPOCH, if set.
+func reproducibleModTime() (time.Time, error) {
+	modTime := filesystem.ReproducibleModTime
+
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q", epoch)
+		}
+		modTime = time.Unix(seconds, 0).UTC()
+	}
+
+	return modTime, nil
+}
+
+// fileCloser closes the underlying file, moving it into place, once the
+// wrapped Filesystem has been closed.
+type fileCloser struct {
+	filesystem.Filesystem
+	f *filesystem.AtomicFile
+}
+
+func (fc *fileCloser) Close() error {
+	err := fc.Filesystem.Close()
+	if err != nil {
+		fc.f.Abort()
+		return err
+	}
+
+	return fc.f.Close()
+}
+
+func (fc *fileCloser) Abort() error {
+	return fc.f.Abort()
+}
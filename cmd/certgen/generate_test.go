@@ -0,0 +1,33 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+func TestReproducibleModTime(t *testing.T) {
+	defer os.Unsetenv("SOURCE_DATE_EPOCH")
+
+	for _, tt := range []struct {
+		epoch   string
+		want    time.Time
+		wantErr bool
+	}{
+		{epoch: "", want: filesystem.ReproducibleModTime},
+		{epoch: "1577934245", want: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)},
+		{epoch: "yesterday", wantErr: true},
+	} {
+		os.Setenv("SOURCE_DATE_EPOCH", tt.epoch)
+
+		modTime, err := reproducibleModTime()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("SOURCE_DATE_EPOCH=%q: error %v", tt.epoch, err)
+		}
+		if !modTime.Equal(tt.want) {
+			t.Errorf("SOURCE_DATE_EPOCH=%q: got %s, want %s", tt.epoch, modTime, tt.want)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/certgen"
+	"github.com/jim-minter/certgen/pkg/filesystem"
+	"gopkg.in/yaml.v2"
+)
+
+func runInspect(args []string) error {
+	flags := newFlagSet("inspect", "TREE [FILE...]", `Show the certificates, private keys and kubeconfigs in TREE, a directory or
+archive previously written by "certgen generate".  If FILEs are given, only
+those files are shown; otherwise every .crt, .key and .kubeconfig file is.`)
+	err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	r, err := filesystem.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	files := flags.Args()[1:]
+	if len(files) == 0 {
+		all, err := r.Files()
+		if err != nil {
+			return err
+		}
+		for _, filename := range all {
+			if fileKind(filename) != kindOther {
+				files = append(files, filename)
+			}
+		}
+	}
+
+	for _, filename := range files {
+		b, err := r.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s:\n", filename)
+		err = inspectFile(os.Stdout, filename, b)
+		if err != nil {
+			return fmt.Errorf("%s: %v", filename, err)
+		}
+	}
+
+	return nil
+}
+
+type kind int
+
+const (
+	kindOther kind = iota
+	kindCertificate
+	kindPrivateKey
+	kindPublicKey
+	kindKubeConfig
+)
+
+// fileKind guesses what a generated file contains from its name.
+func fileKind(filename string) kind {
+	switch {
+	case strings.HasSuffix(filename, ".crt"):
+		return kindCertificate
+	case strings.HasSuffix(filename, ".public.key"):
+		return kindPublicKey
+	case strings.HasSuffix(filename, ".key"):
+		return kindPrivateKey
+	case strings.HasSuffix(filename, ".kubeconfig"):
+		return kindKubeConfig
+	}
+	return kindOther
+}
+
+func inspectFile(w io.Writer, filename string, b []byte) error {
+	switch fileKind(filename) {
+	case kindCertificate:
+		certs, err := certgen.ParseCertificates(b)
+		if err != nil {
+			return err
+		}
+		for _, cert := range certs {
+			printCertificate(w, "  ", cert)
+		}
+
+	case kindPrivateKey:
+		key, err := certgen.ParsePrivateKey(b)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  private key: %s\n", describePublicKey(key.(crypto.Signer).Public()))
+
+	case kindPublicKey:
+		key, err := parsePublicKey(b)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "  public key: %s\n", describePublicKey(key))
+
+	case kindKubeConfig:
+		kc, err := parseKubeConfig(b)
+		if err != nil {
+			return err
+		}
+		printKubeConfig(w, kc)
+
+	default:
+		fmt.Fprintf(w, "  %d bytes\n", len(b))
+	}
+
+	return nil
+}
+
+func printCertificate(w io.Writer, indent string, cert *x509.Certificate) {
+	fmt.Fprintf(w, "%scertificate:\n", indent)
+	fmt.Fprintf(w, "%s  subject: %s\n", indent, cert.Subject)
+	fmt.Fprintf(w, "%s  issuer: %s\n", indent, cert.Issuer)
+	fmt.Fprintf(w, "%s  serial: %s\n", indent, cert.SerialNumber)
+	fmt.Fprintf(w, "%s  validity: %s to %s\n", indent, cert.NotBefore.UTC().Format(time.RFC3339), cert.NotAfter.UTC().Format(time.RFC3339))
+	fmt.Fprintf(w, "%s  public key: %s\n", indent, describePublicKey(cert.PublicKey))
+	if cert.IsCA {
+		fmt.Fprintf(w, "%s  CA: true\n", indent)
+	}
+	if cert.KeyUsage != 0 {
+		fmt.Fprintf(w, "%s  key usage: %s\n", indent, strings.Join(keyUsageNames(cert.KeyUsage), ", "))
+	}
+	if len(cert.ExtKeyUsage) > 0 {
+		fmt.Fprintf(w, "%s  extended key usage: %s\n", indent, strings.Join(extKeyUsageNames(cert.ExtKeyUsage), ", "))
+	}
+	if len(cert.DNSNames) > 0 {
+		fmt.Fprintf(w, "%s  DNS names: %s\n", indent, strings.Join(cert.DNSNames, ", "))
+	}
+	if len(cert.IPAddresses) > 0 {
+		ips := make([]string, 0, len(cert.IPAddresses))
+		for _, ip := range cert.IPAddresses {
+			ips = append(ips, ip.String())
+		}
+		fmt.Fprintf(w, "%s  IP addresses: %s\n", indent, strings.Join(ips, ", "))
+	}
+}
+
+func printKubeConfig(w io.Writer, kc *certgen.KubeConfig) {
+	fmt.Fprintf(w, "  current context: %s\n", kc.CurrentContext)
+	for _, cluster := range kc.Clusters {
+		fmt.Fprintf(w, "  cluster %s:\n", cluster.Name)
+		fmt.Fprintf(w, "    server: %s\n", cluster.Cluster.Server)
+		if certs, err := parseData(cluster.Cluster.CertificateAuthorityData); err == nil {
+			for _, cert := range certs {
+				fmt.Fprintf(w, "    certificate authority: %s\n", cert.Subject)
+			}
+		}
+	}
+	for _, user := range kc.Users {
+		fmt.Fprintf(w, "  user %s:\n", user.Name)
+		if certs, err := parseData(user.User.ClientCertificateData); err == nil {
+			printCertificate(w, "    ", certs[0])
+		}
+	}
+}
+
+func parseData(data string) ([]*x509.Certificate, error) {
+	b, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	return certgen.ParseCertificates(b)
+}
+
+func parseKubeConfig(b []byte) (*certgen.KubeConfig, error) {
+	var kc *certgen.KubeConfig
+	err := yaml.Unmarshal(b, &kc)
+	if err != nil {
+		return nil, err
+	}
+	if kc == nil {
+		return nil, fmt.Errorf("empty kubeconfig")
+	}
+	return kc, nil
+}
+
+func parsePublicKey(b []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, fmt.Errorf("no public key found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+func describePublicKey(key crypto.PublicKey) string {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d bits", key.N.BitLen())
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return fmt.Sprintf("%T", key)
+}
+
+var keyUsages = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "digital signature"},
+	{x509.KeyUsageContentCommitment, "content commitment"},
+	{x509.KeyUsageKeyEncipherment, "key encipherment"},
+	{x509.KeyUsageDataEncipherment, "data encipherment"},
+	{x509.KeyUsageKeyAgreement, "key agreement"},
+	{x509.KeyUsageCertSign, "cert sign"},
+	{x509.KeyUsageCRLSign, "CRL sign"},
+	{x509.KeyUsageEncipherOnly, "encipher only"},
+	{x509.KeyUsageDecipherOnly, "decipher only"},
+}
+
+func keyUsageNames(ku x509.KeyUsage) []string {
+	var names []string
+	for _, u := range keyUsages {
+		if ku&u.usage != 0 {
+			names = append(names, u.name)
+		}
+	}
+	return names
+}
+
+var extKeyUsages = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:             "any",
+	x509.ExtKeyUsageServerAuth:      "server auth",
+	x509.ExtKeyUsageClientAuth:      "client auth",
+	x509.ExtKeyUsageCodeSigning:     "code signing",
+	x509.ExtKeyUsageEmailProtection: "email protection",
+	x509.ExtKeyUsageTimeStamping:    "time stamping",
+	x509.ExtKeyUsageOCSPSigning:     "OCSP signing",
+}
+
+func extKeyUsageNames(ekus []x509.ExtKeyUsage) []string {
+	names := make([]string, 0, len(ekus))
+	for _, eku := range ekus {
+		name, found := extKeyUsages[eku]
+		if !found {
+			name = fmt.Sprintf("unknown (%d)", eku)
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -1,98 +1,148 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"net"
-
-	"github.com/jim-minter/certgen/pkg/certgen"
-	"github.com/jim-minter/certgen/pkg/filesystem"
+	"os"
+	"strings"
 )
 
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{
+		name:        "generate",
+		description: "generate a cluster's certificates and configuration",
+		run:         runGenerate,
+	},
+	{
+		name:        "renew",
+		description: "reissue certificates which are about to expire",
+		run:         runRenew,
+	},
+	{
+		name:        "rotate",
+		description: "replace a cluster's CAs in phases without an outage",
+		run:         runRotate,
+	},
+	{
+		name:        "csr",
+		description: "write CSRs for a cluster's CAs to be signed by an offline root",
+		run:         runCSR,
+	},
+	{
+		name:        "inspect",
+		description: "show the certificates, keys and kubeconfigs in generated output",
+		run:         runInspect,
+	},
+	{
+		name:        "verify",
+		description: "check that generated output is consistent and unexpired",
+		run:         runVerify,
+	},
+	{
+		name:        "diff",
+		description: "compare two sets of generated output",
+		run:         runDiff,
+	},
+}
+
+// exitError exits with the given status without printing anything further.
+type exitError int
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
 func main() {
-	err := run()
-	if err != nil {
-		panic(err)
-	}
+	os.Exit(run(os.Args[1:]))
 }
 
-func run() error {
-	c := certgen.Config{
-		Nodes: []certgen.Node{
-			{
-				Hostname: "master",
-				IPs: []net.IP{
-					net.ParseIP("10.0.0.10"),
-				},
-				Master: &certgen.Master{
-					Port: 8443,
-				},
-			},
-			{
-				Hostname: "node1",
-				IPs: []net.IP{
-					net.ParseIP("10.0.0.11"),
-				},
-			},
-		},
-		ExternalMasterHostname: "jminter2ose.eastus.cloudapp.azure.com",
-		ExternalRouterIP:       net.ParseIP("52.186.12.236"),
+func run(args []string) int {
+	if len(args) == 0 {
+		usage()
+		return 2
 	}
 
-	for i, node := range c.Nodes {
-		if node.Master == nil {
-			continue
-		}
-		err := c.PrepareMasterCerts(&c.Nodes[i])
-		if err != nil {
-			return err
-		}
-		err = c.PrepareMasterKubeConfigs(&c.Nodes[i])
-		if err != nil {
-			return err
-		}
-		err = c.PrepareMasterFiles(&c.Nodes[i])
-		if err != nil {
-			return err
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		if len(args) > 1 {
+			return run([]string{args[1], "-help"})
 		}
+		usage()
+		return 0
 	}
 
-	for i := range c.Nodes {
-		err := c.PrepareNodeCerts(&c.Nodes[i])
-		if err != nil {
-			return err
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
 		}
 
-		err = c.PrepareNodeKubeConfig(&c.Nodes[i])
-		if err != nil {
-			return err
+		err := cmd.run(args[1:])
+		switch err := err.(type) {
+		case nil:
+			return 0
+		case exitError:
+			return int(err)
+		default:
+			if err == flag.ErrHelp {
+				return 0
+			}
+			fmt.Fprintf(os.Stderr, "certgen %s: %v\n", cmd.name, err)
+			return 1
 		}
 	}
 
-	for i, node := range c.Nodes {
-		/*
-			f, err := os.Create(fmt.Sprintf("%s.tgz", node.Hostname))
-			if err != nil {
-				return err
-			}
-			defer f.Close()
+	fmt.Fprintf(os.Stderr, "certgen: unknown command %q\n\n", args[0])
+	usage()
+	return 2
+}
 
-			fs, err := filesystem.NewTGZFile(f)
-			if err != nil {
-				return err
-			}
-		*/
-		fs, err := filesystem.NewFilesystem(fmt.Sprintf("%s/%s", c.ExternalMasterHostname, node.Hostname))
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: certgen <command> [arguments]\n\ncommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.description)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun \"certgen help <command>\" for more information about a command.\n")
+}
 
-		err = c.WriteNode(fs, &c.Nodes[i])
-		if err != nil {
-			return err
-		}
+func newFlagSet(name, synopsis, help string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: certgen %s %s\n\n%s\n", name, synopsis, help)
 
-		err = fs.Close()
-		if err != nil {
-			return err
+		var hasFlags bool
+		flags.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintf(flags.Output(), "\nflags:\n")
+			flags.PrintDefaults()
 		}
 	}
+	return flags
+}
+
+func parseFlags(flags *flag.FlagSet, args []string, minArgs int) error {
+	err := flags.Parse(args)
+	if err == flag.ErrHelp {
+		return err
+	}
+	if err != nil {
+		return exitError(2)
+	}
+
+	if flags.NArg() < minArgs {
+		return usageErrorf(flags, "expected at least %d argument(s)", minArgs)
+	}
 
 	return nil
 }
+
+func usageErrorf(flags *flag.FlagSet, format string, a ...interface{}) error {
+	fmt.Fprintf(flags.Output(), "certgen %s: %s\n", flags.Name(), strings.TrimSpace(fmt.Sprintf(format, a...)))
+	flags.Usage()
+	return exitError(2)
+}
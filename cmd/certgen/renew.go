@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/certgen"
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+func runRenew(args []string) error {
+	flags := newFlagSet("renew", "-config FILE [flags]", `Reissue the leaf certificates previously written by "certgen generate" for
+the cluster described by FILE which expire within the -within duration.  Each
+certificate is reissued with the same subject, usages and SANs under the same
+CA, and the kubeconfigs which embed it are regenerated; everything else is kept
+as it is.  CAs are not renewed.
+
+Every file which changed is listed.`)
+	configFile := flags.String("config", "", "cluster description `file` (YAML or JSON)")
+	output := addOutputFlags(flags)
+	within := flags.Duration("within", 30*24*time.Hour, "renew certificates expiring within `duration`")
+	keepKeys := flags.Bool("keep-keys", false, "keep the existing private keys instead of generating new ones")
+	err := parseFlags(flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return usageErrorf(flags, "unexpected argument %q", flags.Arg(0))
+	}
+	if *configFile == "" {
+		return usageErrorf(flags, "-config must be specified")
+	}
+
+	d, err := certgen.LoadClusterDescription(*configFile)
+	if err != nil {
+		return err
+	}
+
+	err = output.apply(d)
+	if err != nil {
+		return err
+	}
+
+	c, err := d.Config()
+	if err != nil {
+		return err
+	}
+
+	old, err := loadTrees(d, c)
+	if err != nil {
+		return err
+	}
+
+	var renewed int
+	deadline := time.Now().Add(*within)
+	for _, host := range hosts(c) {
+		filenames, err := c.RenewCerts(host, deadline, *keepKeys)
+		if err != nil {
+			return fmt.Errorf("%s: %v", outputName(d, host.Hostname), err)
+		}
+		renewed += len(filenames)
+	}
+
+	if renewed == 0 {
+		fmt.Printf("no certificates expire within %s\n", *within)
+		return nil
+	}
+
+	err = prepare(c)
+	if err != nil {
+		return err
+	}
+
+	err = write(d, c)
+	if err != nil {
+		return err
+	}
+
+	return reportChanges(d, c, old)
+}
+
+// loadTrees returns a copy of the existing output to pass to reportChanges.
+func loadTrees(from *certgen.ClusterDescription, c *certgen.Config) (map[string]map[string][]byte, error) {
+	old := map[string]map[string][]byte{}
+
+	for _, host := range hosts(c) {
+		name := outputName(from, host.Hostname)
+
+		r, err := filesystem.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("%v (run \"certgen generate\" first)", err)
+		}
+
+		// writing may replace the output, so keep a copy to compare against
+		old[host.Hostname], err = readTree(r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+
+		err = c.Load(r, host)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	return old, nil
+}
+
+func reportChanges(d *certgen.ClusterDescription, c *certgen.Config, old map[string]map[string][]byte) error {
+	for _, host := range hosts(c) {
+		name := outputName(d, host.Hostname)
+
+		r, err := filesystem.Open(name)
+		if err != nil {
+			return err
+		}
+
+		tree, err := readTree(r)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+
+		for _, filename := range changedFiles(old[host.Hostname], tree) {
+			fmt.Printf("%s: %s\n", name, filename)
+		}
+	}
+
+	return nil
+}
+
+func readTree(r filesystem.Reader) (map[string][]byte, error) {
+	files, err := r.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	tree := make(map[string][]byte, len(files))
+	for _, filename := range files {
+		tree[filename], err = r.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tree, nil
+}
+
+func changedFiles(old, tree map[string][]byte) []string {
+	var changed []string
+	for filename, b := range old {
+		if nb, found := tree[filename]; !found || !bytes.Equal(b, nb) {
+			changed = append(changed, filename)
+		}
+	}
+	for filename := range tree {
+		if _, found := old[filename]; !found {
+			changed = append(changed, filename)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/jim-minter/certgen/pkg/certgen"
+)
+
+func runRotate(args []string) error {
+	flags := newFlagSet("rotate", "-config FILE -phase PHASE [flags]", `Replace CAs of the cluster described by FILE without an outage.  Rotation
+takes three phases, each of which reads the output of the previous one (or of
+"certgen generate"), writes a complete new output and must be rolled out to
+every host before the next phase is run:
+
+  trust    create a new CA for each of the -ca CAs; CA bundles and
+           kubeconfigs trust both the old and new CAs
+  reissue  sign with the new CAs and reissue every certificate under them;
+           the old CAs are still trusted
+  finish   stop trusting the old CAs
+
+By default each phase replaces its input; use -from to read the input from
+elsewhere and keep each phase's output separately.  Every file which changed
+is listed.`)
+	configFile := flags.String("config", "", "cluster description `file` (YAML or JSON)")
+	output := addOutputFlags(flags)
+	from := flags.String("from", "", "read the previous phase's output from `directory` (default the output directory)")
+	phase := flags.String("phase", "", "rotation `phase`: trust, reissue or finish")
+	cas := flags.String("ca", "", "comma-separated `names` of the CAs to rotate (trust phase; default those of the cluster's CAs not supplied in the description)")
+	err := parseFlags(flags, args, 0)
+	if err != nil {
+		return err
+	}
+
+	if flags.NArg() > 0 {
+		return usageErrorf(flags, "unexpected argument %q", flags.Arg(0))
+	}
+	if *configFile == "" {
+		return usageErrorf(flags, "-config must be specified")
+	}
+	switch *phase {
+	case "trust", "reissue", "finish":
+	case "":
+		return usageErrorf(flags, "-phase must be specified")
+	default:
+		return usageErrorf(flags, "unknown phase %q", *phase)
+	}
+
+	d, err := certgen.LoadClusterDescription(*configFile)
+	if err != nil {
+		return err
+	}
+
+	err = output.apply(d)
+	if err != nil {
+		return err
+	}
+
+	c, err := d.Config()
+	if err != nil {
+		return err
+	}
+
+	in := *d
+	if *from != "" {
+		in.Output.Path = *from
+	}
+
+	old, err := loadTrees(&in, c)
+	if err != nil {
+		return err
+	}
+
+	switch *phase {
+	case "trust":
+		var names []string
+		if *cas != "" {
+			names = strings.Split(*cas, ",")
+		}
+		err = c.BeginCARotation(names...)
+	case "reissue":
+		err = c.PromoteCAs()
+	case "finish":
+		err = c.FinishCARotation()
+	}
+	if err != nil {
+		return err
+	}
+
+	err = prepare(c)
+	if err != nil {
+		return err
+	}
+
+	err = write(d, c)
+	if err != nil {
+		return err
+	}
+
+	return reportChanges(d, c, old)
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/certgen"
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+func runVerify(args []string) error {
+	flags := newFlagSet("verify", "[flags] TREE...", `Check each TREE, a directory or archive previously written by "certgen
+generate", for problems: certificates which do not chain to a CA in the tree,
+private keys which do not match their certificate, and certificates which have
+expired (or expire within the -expires-within duration).  kubeconfigs are
+checked against the certificate authority data they embed.
+
+The exit status is 1 if any problem is found.`)
+	expiresWithin := flags.Duration("expires-within", 0, "report certificates expiring within `duration` (e.g. 720h)")
+	err := parseFlags(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var problems int
+	for _, name := range flags.Args() {
+		r, err := filesystem.Open(name)
+		if err != nil {
+			return err
+		}
+
+		errs, err := verifyTree(r, time.Now().Add(*expiresWithin))
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+
+		for _, err := range errs {
+			fmt.Printf("%s: %v\n", name, err)
+		}
+		problems += len(errs)
+	}
+
+	if problems > 0 {
+		fmt.Printf("%d problem(s) found\n", problems)
+		return exitError(1)
+	}
+
+	return nil
+}
+
+// Certificates which are no longer valid at deadline are reported as expiring.
+func verifyTree(r filesystem.Reader, deadline time.Time) ([]error, error) {
+	files, err := r.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	errorf := func(format string, a ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, a...))
+	}
+
+	certs := map[string][]*x509.Certificate{}
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+
+	for _, filename := range files {
+		if fileKind(filename) != kindCertificate {
+			continue
+		}
+
+		b, err := r.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		certs[filename], err = certgen.ParseCertificates(b)
+		if err != nil {
+			errorf("%s: %v", filename, err)
+			continue
+		}
+
+		for _, cert := range certs[filename] {
+			if !cert.IsCA {
+				continue
+			}
+			if bytes.Equal(cert.RawIssuer, cert.RawSubject) {
+				roots.AddCert(cert)
+			} else {
+				intermediates.AddCert(cert)
+			}
+		}
+	}
+
+	for _, filename := range files {
+		switch fileKind(filename) {
+		case kindCertificate:
+			if certs[filename] == nil {
+				continue
+			}
+
+			for _, err := range verifyCertificate(certs[filename][0], roots, intermediates, deadline) {
+				errorf("%s: %v", filename, err)
+			}
+
+			keyfile := strings.TrimSuffix(filename, ".crt") + ".key"
+			b, err := r.ReadFile(keyfile)
+			if err != nil {
+				// not every certificate is accompanied by its key
+				continue
+			}
+
+			err = verifyKeyPair(certs[filename][0], b)
+			if err != nil {
+				errorf("%s: %v", keyfile, err)
+			}
+
+		case kindKubeConfig:
+			b, err := r.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, err := range verifyKubeConfig(b, deadline) {
+				errorf("%s: %v", filename, err)
+			}
+		}
+	}
+
+	return errs, nil
+}
+
+func verifyCertificate(cert *x509.Certificate, roots, intermediates *x509.CertPool, deadline time.Time) []error {
+	var errs []error
+
+	now := time.Now()
+	switch {
+	case now.Before(cert.NotBefore):
+		errs = append(errs, fmt.Errorf("certificate is not valid until %s", cert.NotBefore.UTC().Format(time.RFC3339)))
+	case now.After(cert.NotAfter):
+		errs = append(errs, fmt.Errorf("certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339)))
+	case deadline.After(cert.NotAfter):
+		errs = append(errs, fmt.Errorf("certificate expires at %s", cert.NotAfter.UTC().Format(time.RFC3339)))
+	}
+
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   cert.NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+func verifyKeyPair(cert *x509.Certificate, b []byte) error {
+	key, err := certgen.ParsePrivateKey(b)
+	if err != nil {
+		return err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	certpub, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	keypub, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(certpub, keypub) {
+		return fmt.Errorf("private key does not match certificate")
+	}
+
+	return nil
+}
+
+func verifyKubeConfig(b []byte, deadline time.Time) []error {
+	kc, err := parseKubeConfig(b)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+	for _, cluster := range kc.Clusters {
+		cas, err := parseData(cluster.Cluster.CertificateAuthorityData)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: certificate-authority-data: %v", cluster.Name, err))
+			continue
+		}
+		for _, ca := range cas {
+			if bytes.Equal(ca.RawIssuer, ca.RawSubject) {
+				roots.AddCert(ca)
+			} else {
+				intermediates.AddCert(ca)
+			}
+		}
+	}
+
+	for _, user := range kc.Users {
+		certs, err := parseData(user.User.ClientCertificateData)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: client-certificate-data: %v", user.Name, err))
+			continue
+		}
+
+		for _, err := range verifyCertificate(certs[0], roots, intermediates, deadline) {
+			errs = append(errs, fmt.Errorf("user %s: %v", user.Name, err))
+		}
+
+		key, err := base64.StdEncoding.DecodeString(user.User.ClientKeyData)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: client-key-data: %v", user.Name, err))
+			continue
+		}
+
+		err = verifyKeyPair(certs[0], key)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %v", user.Name, err))
+		}
+	}
+
+	return errs
+}
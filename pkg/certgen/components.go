@@ -0,0 +1,304 @@
+package certgen
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ComponentLogging = "logging"
+	ComponentMetrics = "metrics"
+)
+
+var ComponentNames = []string{ComponentLogging, ComponentMetrics}
+
+var componentCertNames = map[string][]string{
+	ComponentLogging: {
+		"logging-curator", "logging-elasticsearch", "logging-elasticsearch-admin",
+		"logging-fluentd", "logging-kibana", "logging-kibana-proxy",
+	},
+	ComponentMetrics: {"hawkular-cassandra", "hawkular-metrics", "heapster"},
+}
+
+func (c *Config) EnableComponent(name string, dedicatedCA bool) error {
+	if _, found := componentCertNames[name]; !found {
+		return fmt.Errorf("unknown component %q (expected one of %s)", name, strings.Join(ComponentNames, ", "))
+	}
+
+	if c.components == nil {
+		c.components = map[string]bool{}
+	}
+	c.components[name] = dedicatedCA
+
+	return nil
+}
+
+func (c *Config) componentCA(name string) string {
+	if c.components[name] {
+		return name + "-ca"
+	}
+	return "ca"
+}
+
+func (c *Config) serviceNames(service, namespace string) []string {
+	return []string{
+		service,
+		fmt.Sprintf("%s.%s.svc", service, namespace),
+		fmt.Sprintf("%s.%s.svc.%s", service, namespace, c.DNSDomain),
+	}
+}
+
+func (c *Config) componentCertTemplates(name string) []struct {
+	filename  string
+	namespace string
+	template  *x509.Certificate
+} {
+	// Elasticsearch identifies its clients by their whole subject
+	loggingClient := func(cn string) pkix.Name {
+		return pkix.Name{Organization: []string{"Logging"}, OrganizationalUnit: []string{"OpenShift"}, CommonName: cn}
+	}
+
+	switch name {
+	case ComponentLogging:
+		return []struct {
+			filename  string
+			namespace string
+			template  *x509.Certificate
+		}{
+			{
+				filename:  "logging-curator",
+				namespace: "logging",
+				template: &x509.Certificate{
+					Subject:     loggingClient("system.logging.curator"),
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+				},
+			},
+			{
+				filename:  "logging-elasticsearch",
+				namespace: "logging",
+				template: &x509.Certificate{
+					Subject:     pkix.Name{CommonName: "logging-es"},
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+					DNSNames:    append(append(c.serviceNames("logging-es", "logging"), c.serviceNames("logging-es-cluster", "logging")...), "localhost"),
+				},
+			},
+			{
+				filename:  "logging-elasticsearch-admin",
+				namespace: "logging",
+				template: &x509.Certificate{
+					Subject:     loggingClient("system.admin"),
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+				},
+			},
+			{
+				filename:  "logging-fluentd",
+				namespace: "logging",
+				template: &x509.Certificate{
+					Subject:     loggingClient("system.logging.fluentd"),
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+				},
+			},
+			{
+				filename:  "logging-kibana",
+				namespace: "logging",
+				template: &x509.Certificate{
+					Subject:     loggingClient("system.logging.kibana"),
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+				},
+			},
+			{
+				filename:  "logging-kibana-proxy",
+				namespace: "logging",
+				template: &x509.Certificate{
+					Subject:     pkix.Name{CommonName: "kibana." + c.RoutingSubdomain()},
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+					DNSNames:    []string{"kibana." + c.RoutingSubdomain()},
+				},
+			},
+		}
+
+	case ComponentMetrics:
+		return []struct {
+			filename  string
+			namespace string
+			template  *x509.Certificate
+		}{
+			{
+				filename:  "hawkular-cassandra",
+				namespace: "openshift-infra",
+				template: &x509.Certificate{
+					Subject:     pkix.Name{CommonName: "hawkular-cassandra"},
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+					DNSNames:    c.serviceNames("hawkular-cassandra", "openshift-infra"),
+				},
+			},
+			{
+				filename:  "hawkular-metrics",
+				namespace: "openshift-infra",
+				template: &x509.Certificate{
+					Subject:     pkix.Name{CommonName: "hawkular-metrics"},
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+					DNSNames:    append(c.serviceNames("hawkular-metrics", "openshift-infra"), "hawkular-metrics."+c.RoutingSubdomain()),
+				},
+			},
+			{
+				filename:  "heapster",
+				namespace: "openshift-infra",
+				template: &x509.Certificate{
+					Subject:     pkix.Name{CommonName: "heapster"},
+					ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+					DNSNames:    c.serviceNames("heapster", "openshift-infra"),
+				},
+			},
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) PrepareComponentCerts() error {
+	c.componentCerts = map[string]CertAndKey{}
+
+	now := time.Now()
+
+	for _, name := range ComponentNames {
+		if _, enabled := c.components[name]; !enabled {
+			continue
+		}
+		signer := c.componentCA(name)
+
+		for _, cert := range c.componentCertTemplates(name) {
+			template := &x509.Certificate{
+				NotBefore:             now,
+				NotAfter:              now.AddDate(2, 0, 0),
+				KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+				BasicConstraintsValid: true,
+			}
+			template.Subject = cert.template.Subject
+			template.ExtKeyUsage = cert.template.ExtKeyUsage
+			template.DNSNames = cert.template.DNSNames
+			c.applyProfile(cert.filename, template, c.cas[signer].cert, now)
+
+			spec := c.keySpec(KeyClassComponent)
+			key := cert.namespace + "/" + cert.filename
+
+			if existing, found := c.existingComponentCerts[key]; found && c.reusable(existing, template, signer, spec) {
+				c.componentCerts[key] = existing
+				continue
+			}
+			serialNumber, err := c.serial.Get()
+			if err != nil {
+				return err
+			}
+			template.SerialNumber = serialNumber
+
+			certAndKey, err := newCertAndKey(cert.filename, template, c.cas[signer].cert, c.cas[signer].key, spec, false, false)
+			if err != nil {
+				return err
+			}
+
+			c.componentCerts[key] = certAndKey
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) componentSecrets() ([]*Secret, error) {
+	var secrets []*Secret
+
+	for _, name := range ComponentNames {
+		if _, enabled := c.components[name]; !enabled {
+			continue
+		}
+
+		for _, cert := range c.componentCertTemplates(name) {
+			secret, err := tlsSecret(cert.filename, cert.namespace, c.componentCerts[cert.namespace+"/"+cert.filename], c.keySpec(KeyClassComponent).Encoding, c.caBundle(c.componentCA(name)))
+			if err != nil {
+				return nil, err
+			}
+
+			secrets = append(secrets, secret)
+		}
+	}
+
+	return secrets, nil
+}
+
+func (c *Config) WriteComponentSecrets(fs filesystem.Filesystem) error {
+	secrets, err := c.componentSecrets()
+	if err != nil {
+		return err
+	}
+
+	for _, secret := range secrets {
+		err = writeManifest(fs, fmt.Sprintf("etc/origin/master/secrets/%s/%s.yaml", secret.Metadata.Namespace, secret.Metadata.Name), secret)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Secrets which cannot be read back are ignored.
+func (c *Config) loadComponentSecrets(r filesystem.Reader, exists map[string]bool) (map[string]CertAndKey, error) {
+	const prefix = "etc/origin/master/secrets/"
+
+	certs := map[string]CertAndKey{}
+
+	for filename := range exists {
+		if !strings.HasPrefix(filename, prefix) || !strings.HasSuffix(filename, ".yaml") {
+			continue
+		}
+
+		b, err := r.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		var secret Secret
+		err = yaml.Unmarshal(b, &secret)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+
+		crt, err := base64.StdEncoding.DecodeString(secret.Data["tls.crt"])
+		if err != nil {
+			continue
+		}
+
+		parsed, err := ParseCertificates(crt)
+		if err != nil {
+			continue
+		}
+		c.serial.Observe(parsed[0].SerialNumber)
+
+		key, err := base64.StdEncoding.DecodeString(secret.Data["tls.key"])
+		if err != nil {
+			continue
+		}
+
+		privkey, err := ParsePrivateKey(key)
+		if err != nil {
+			continue
+		}
+
+		signer, ok := privkey.(crypto.Signer)
+		if !ok {
+			continue
+		}
+
+		certs[secret.Metadata.Namespace+"/"+secret.Metadata.Name] = CertAndKey{cert: parsed[0], key: signer}
+	}
+
+	return certs, nil
+}
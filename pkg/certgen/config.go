@@ -1,59 +1,219 @@
 package certgen
 
 import (
+	"crypto"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"math/big"
 	"net"
+	"strconv"
 	"sync"
 
 	"github.com/jim-minter/certgen/pkg/filesystem"
 )
 
+// Defaults of the cluster's internal networks.
+const (
+	DefaultServiceCIDR      = "172.30.0.0/16"
+	DefaultClusterCIDR      = "10.128.0.0/14"
+	DefaultHostSubnetLength = 9
+	DefaultDNSDomain        = "cluster.local"
+)
+
 type Config struct {
 	Nodes                  []Node
+	EtcdHosts              []Node
 	ExternalRouterIP       net.IP
+	RouterSubdomain        string
 	ExternalMasterHostname string
+	ServiceNetwork         *net.IPNet
+	ClusterNetwork         *net.IPNet
+	HostSubnetLength       int
+	DNSDomain              string
+	RegistryClusterIP      net.IP
+	RegistryHostname       string
+	EmitManifests          bool
+	EmitManifestList       bool
 	serial                 serial
 	cas                    map[string]CertAndKey
+	suppliedCAs            map[string]bool
+	rootCA                 *CertAndKey
+	nextCAs                map[string]CertAndKey
+	previousCAs            map[string]*x509.Certificate
+	keySpecs               map[string]KeySpec
+	profiles               map[string]Profile
+	components             map[string]bool
+	componentCerts         map[string]CertAndKey
+	existingComponentCerts map[string]CertAndKey
+	clusterCerts           map[string]CertAndKey
+	existingClusterCerts   map[string]CertAndKey
+	named                  *namedCertificate
+	cacsrs                 map[string]csrAndKey
+	serviceAccountKey      *rsa.PrivateKey
 	AuthSecret             string
 	EncSecret              string
 }
 
+// RoutingSubdomain is RouterSubdomain if set, otherwise <ExternalRouterIP>.nip.io.
+func (c *Config) RoutingSubdomain() string {
+	if c.RouterSubdomain != "" {
+		return c.RouterSubdomain
+	}
+	return c.ExternalRouterIP.String() + ".nip.io"
+}
+
+// KubernetesServiceIP is the first address in the service network.
+func (c *Config) KubernetesServiceIP() net.IP {
+	return serviceIP(c.ServiceNetwork, 1)
+}
+
+// RegistryServiceIP is RegistryClusterIP if set, otherwise the second address
+// in the service network.
+func (c *Config) RegistryServiceIP() net.IP {
+	if c.RegistryClusterIP != nil {
+		return c.RegistryClusterIP
+	}
+	return serviceIP(c.ServiceNetwork, 2)
+}
+
+func serviceIP(network *net.IPNet, n byte) net.IP {
+	ip := make(net.IP, len(network.IP))
+	copy(ip, network.IP)
+	for i := len(ip) - 1; i >= 0; i-- {
+		ip[i] += n
+		if ip[i] >= n {
+			break
+		}
+		n = 1
+	}
+	return ip
+}
+
+func (c *Config) Masters() []*Node {
+	var masters []*Node
+	for i := range c.Nodes {
+		if c.Nodes[i].Master != nil {
+			masters = append(masters, &c.Nodes[i])
+		}
+	}
+	return masters
+}
+
+// EtcdMembers returns the nodes with Etcd set, then the dedicated etcd hosts.
+func (c *Config) EtcdMembers() []*Node {
+	var members []*Node
+	for i := range c.Nodes {
+		if c.Nodes[i].Etcd != nil {
+			members = append(members, &c.Nodes[i])
+		}
+	}
+	for i := range c.EtcdHosts {
+		members = append(members, &c.EtcdHosts[i])
+	}
+	return members
+}
+
 type openShiftConfig struct {
 	certs       map[string]CertAndKey
 	kubeconfigs map[string]KubeConfig
+	existing    map[string]CertAndKey
 }
 
 type Node struct {
 	Hostname string
 	IPs      []net.IP
 	Master   *Master
+	Etcd     *Etcd
 	openShiftConfig
 }
 
+func (n *Node) PrimaryIP() net.IP {
+	return n.IPs[0]
+}
+
+// BindNetwork is tcp4 or tcp6 if all of the node's addresses are IPv4 or IPv6
+// respectively, otherwise tcp.
+func (n *Node) BindNetwork() string {
+	var v4, v6 bool
+	for _, ip := range n.IPs {
+		if ip.To4() != nil {
+			v4 = true
+		} else {
+			v6 = true
+		}
+	}
+
+	switch {
+	case v4 && !v6:
+		return "tcp4"
+	case v6 && !v4:
+		return "tcp6"
+	}
+	return "tcp"
+}
+
+func (n *Node) BindAddress(port int16) string {
+	host := "::"
+	if n.BindNetwork() == "tcp4" {
+		host = "0.0.0.0"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(port)))
+}
+
 type Master struct {
 	Port int16
 	openShiftConfig
-	etcdcerts map[string]CertAndKey
+}
+
+type Etcd struct {
+	certs    map[string]CertAndKey
+	existing map[string]CertAndKey
 }
 
 type CertAndKey struct {
 	cert *x509.Certificate
-	key  *rsa.PrivateKey
+	key  crypto.Signer
+}
+
+type csrAndKey struct {
+	csr *x509.CertificateRequest
+	key crypto.Signer
 }
 
 type serial struct {
-	m sync.Mutex
-	i int64
+	m      sync.Mutex
+	i      int64
+	random bool
 }
 
-func (s *serial) Get() *big.Int {
+// serialLimit bounds random serial numbers to 128 bits.
+var serialLimit = new(big.Int).Lsh(big.NewInt(1), 128)
+
+func (s *serial) Get() (*big.Int, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
+	if s.random {
+		return rand.Int(rand.Reader, serialLimit)
+	}
+
 	s.i++
-	return big.NewInt(s.i)
+	return big.NewInt(s.i), nil
+}
+
+// Observe records an issued serial number, so that Get does not reissue it.
+func (s *serial) Observe(i *big.Int) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	switch {
+	case !i.IsInt64():
+		// not one of ours: avoid collisions by switching to random serials
+		s.random = true
+	case i.Int64() > s.i:
+		s.i = i.Int64()
+	}
 }
 
 func (c *Config) writeMaster(fs filesystem.Filesystem, node *Node) error {
@@ -77,6 +237,31 @@ func (c *Config) writeMaster(fs filesystem.Filesystem, node *Node) error {
 		return err
 	}
 
+	err = c.WriteComponentSecrets(fs)
+	if err != nil {
+		return err
+	}
+
+	err = c.WriteMasterManifests(fs, node)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// WriteEtcd is also called directly for dedicated etcd hosts.
+func (c *Config) WriteEtcd(fs filesystem.Filesystem, node *Node) error {
+	err := c.WriteEtcdCerts(fs, node)
+	if err != nil {
+		return err
+	}
+
+	err = c.WriteEtcdFiles(fs, node)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -88,6 +273,13 @@ func (c *Config) WriteNode(fs filesystem.Filesystem, node *Node) error {
 		}
 	}
 
+	if node.Etcd != nil {
+		err := c.WriteEtcd(fs, node)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := c.WriteNodeCerts(fs, node)
 	if err != nil {
 		return err
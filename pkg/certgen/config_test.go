@@ -0,0 +1,123 @@
+package certgen
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+const testDescription = `
+apiVersion: certgen/v1
+kind: ClusterDescription
+externalMasterHostname: master.example.com
+externalRouterIP: 10.0.0.100
+nodes:
+- hostname: master1
+  ips: [10.0.0.1]
+  master: {port: 8443}
+- hostname: node1
+  ips: [10.0.0.2]
+keys:
+  ca: {algorithm: ecdsa-p256}
+  etcd-ca: {algorithm: ecdsa-p256}
+  master: {algorithm: ecdsa-p256}
+  etcd: {algorithm: ecdsa-p256}
+  node: {algorithm: ecdsa-p256}
+  router: {algorithm: ecdsa-p256}
+`
+
+func testConfig(t *testing.T) *Config {
+	return testConfigFor(t, testDescription)
+}
+
+func testConfigFor(t *testing.T, description string) *Config {
+	d, err := ParseClusterDescription([]byte(description))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := d.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.PrepareCAs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range c.Nodes {
+		node := &c.Nodes[i]
+		if node.Master != nil {
+			for _, prepare := range []func(*Node) error{c.PrepareMasterCerts, c.PrepareMasterKeypair, c.PrepareMasterKubeConfigs, c.PrepareMasterFiles} {
+				err = prepare(node)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+	}
+
+	for _, member := range c.EtcdMembers() {
+		err = c.PrepareEtcdCerts(member)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := range c.Nodes {
+		for _, prepare := range []func(*Node) error{c.PrepareNodeCerts, c.PrepareNodeKubeConfig} {
+			err = prepare(&c.Nodes[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	return c
+}
+
+func TestWriteNode(t *testing.T) {
+	c := testConfig(t)
+
+	fs := filesystem.NewMemoryFilesystem()
+	err := c.WriteNode(fs, &c.Nodes[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]os.FileMode{
+		"etc/docker/certs.d/172.30.0.2:5000/ca.crt":                  0666,
+		"etc/docker/certs.d/docker-registry.default.svc:5000/ca.crt": 0666,
+		"etc/origin/node/ca.crt":                                     0666,
+		"etc/origin/node/node-client-ca.crt":                         0666,
+		"etc/origin/node/node-config.yaml":                           0666,
+		"etc/origin/node/node-dnsmasq.conf":                          0666,
+		"etc/origin/node/resolv.conf":                                0666,
+		"etc/origin/node/server.crt":                                 0666,
+		"etc/origin/node/server.key":                                 0600,
+		"etc/origin/node/system:node:node1.crt":                      0666,
+		"etc/origin/node/system:node:node1.key":                      0600,
+		"etc/origin/node/system:node:node1.kubeconfig":               0600,
+	}
+
+	files, err := fs.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]os.FileMode{}
+	for _, filename := range files {
+		info, err := fs.Stat(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[filename] = info.Mode()
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteNode wrote %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,905 @@
+package certgen
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+	"gopkg.in/yaml.v2"
+)
+
+// DescriptionAPIVersion and DescriptionKind identify the cluster description
+// file format understood by this version of certgen.  The format is YAML; as
+// JSON is a subset of YAML, JSON documents are accepted too.
+const (
+	DescriptionAPIVersion = "certgen/v1"
+	DescriptionKind       = "ClusterDescription"
+)
+
+// Output formats supported in a cluster description.
+const (
+	OutputFormatDirectory = "directory"
+	OutputFormatTGZ       = "tgz"
+	OutputFormatZip       = "zip"
+	OutputFormatCPIO      = "cpio"
+	OutputFormatCloudInit = "cloud-init"
+	OutputFormatIgnition  = "ignition"
+)
+
+var OutputFormats = []string{
+	OutputFormatDirectory, OutputFormatTGZ, OutputFormatZip, OutputFormatCPIO,
+	OutputFormatCloudInit, OutputFormatIgnition,
+}
+
+// ClusterDescription is the on-disk description of a cluster from which a
+// Config is built.  See cluster.example.yaml for an annotated example.
+type ClusterDescription struct {
+	// APIVersion must be DescriptionAPIVersion.
+	APIVersion string `yaml:"apiVersion"`
+	// Kind must be DescriptionKind.
+	Kind string `yaml:"kind"`
+
+	// ExternalMasterHostname is the public hostname of the master API.
+	ExternalMasterHostname string `yaml:"externalMasterHostname"`
+	// ExternalRouterIP is only required if RouterSubdomain is not set.
+	ExternalRouterIP string `yaml:"externalRouterIP,omitempty"`
+	// RouterSubdomain defaults to <ExternalRouterIP>.nip.io.
+	RouterSubdomain string `yaml:"routerSubdomain,omitempty"`
+
+	Network NetworkDescription `yaml:"network,omitempty"`
+
+	// Components is keyed by component name (one of ComponentNames).
+	Components map[string]ComponentDescription `yaml:"components,omitempty"`
+
+	Registry RegistryDescription `yaml:"registry,omitempty"`
+
+	// Nodes lists every host in the cluster, masters included.
+	Nodes []NodeDescription `yaml:"nodes"`
+	// EtcdHosts lists dedicated etcd members, which are not OpenShift nodes.
+	EtcdHosts []EtcdHostDescription `yaml:"etcdHosts,omitempty"`
+
+	// CAs lists existing CAs to use instead of generating them, keyed by name.
+	CAs map[string]CADescription `yaml:"cas,omitempty"`
+
+	// RootCA's key may be omitted if every CA is listed in CAs.
+	RootCA *CADescription `yaml:"rootCA,omitempty"`
+
+	// NamedCertificate is served to clients of the external master hostname.
+	NamedCertificate *NamedCertificateDescription `yaml:"namedCertificate,omitempty"`
+
+	// Keys is keyed by key class; classes which are not listed use RSA keys.
+	Keys map[string]KeyDescription `yaml:"keys,omitempty"`
+
+	// Profiles is keyed by certificate name or path.Match pattern.
+	Profiles map[string]ProfileDescription `yaml:"profiles,omitempty"`
+
+	Manifests *ManifestsDescription `yaml:"manifests,omitempty"`
+
+	// Output describes where generated files are written.
+	Output OutputDescription `yaml:"output,omitempty"`
+
+	// dir is the directory against which relative paths are resolved.
+	dir string
+}
+
+type CADescription struct {
+	Cert string `yaml:"cert"`
+	Key  string `yaml:"key"`
+}
+
+// Either Cert and Key are given, or Generate is set.
+type NamedCertificateDescription struct {
+	// Cert is followed by any intermediates.
+	Cert string `yaml:"cert,omitempty"`
+	Key  string `yaml:"key,omitempty"`
+	// CA holds the CA certificates which trust Cert.
+	CA string `yaml:"ca,omitempty"`
+	// Generate has public-ca sign a generated certificate instead.
+	Generate bool `yaml:"generate,omitempty"`
+}
+
+type KeyDescription struct {
+	Algorithm string `yaml:"algorithm"`
+	// Encoding defaults to pkcs1 for RSA and sec1 for ECDSA keys.
+	Encoding string `yaml:"encoding,omitempty"`
+}
+
+// Fields which are not set keep their defaults.
+type ProfileDescription struct {
+	// Validity is a Go duration (e.g. 8760h) or a number of days (e.g. 365d).
+	Validity string `yaml:"validity,omitempty"`
+	// KeyUsages replaces the key usages; see KeyUsageNames.
+	KeyUsages []string `yaml:"keyUsages,omitempty"`
+	// ExtKeyUsages replaces the extended key usages; see ExtKeyUsageNames.
+	ExtKeyUsages []string `yaml:"extKeyUsages,omitempty"`
+	// ExtraDNSNames and ExtraIPs are added to the subject alternative names.
+	ExtraDNSNames []string            `yaml:"extraDNSNames,omitempty"`
+	ExtraIPs      []string            `yaml:"extraIPs,omitempty"`
+	Subject       *SubjectDescription `yaml:"subject,omitempty"`
+}
+
+type SubjectDescription struct {
+	CommonName    string   `yaml:"commonName,omitempty"`
+	Organizations []string `yaml:"organizations,omitempty"`
+}
+
+type NetworkDescription struct {
+	// ServiceCIDR's first address is the kubernetes service's.
+	ServiceCIDR string `yaml:"serviceCIDR,omitempty"`
+	ClusterCIDR string `yaml:"clusterCIDR,omitempty"`
+	// HostSubnetLength is the number of pod address bits allocated to each node.
+	HostSubnetLength int    `yaml:"hostSubnetLength,omitempty"`
+	DNSDomain        string `yaml:"dnsDomain,omitempty"`
+}
+
+type RegistryDescription struct {
+	// ClusterIP defaults to the second address in the service network.
+	ClusterIP string `yaml:"clusterIP,omitempty"`
+	// Hostname is the registry's external route hostname, if any.
+	Hostname string `yaml:"hostname,omitempty"`
+}
+
+type ComponentDescription struct {
+	// CA is "main" (the default) or "dedicated", for the component's own CA.
+	CA string `yaml:"ca,omitempty"`
+}
+
+// NodeDescription describes a single host.
+type NodeDescription struct {
+	// Hostname is the node's (unique) hostname.
+	Hostname string   `yaml:"hostname"`
+	IPs      []string `yaml:"ips"`
+	// PrimaryIP defaults to the first of IPs.
+	PrimaryIP string `yaml:"primaryIP,omitempty"`
+	// Master is set if the node runs the master components.
+	Master *MasterDescription `yaml:"master,omitempty"`
+	// If no node sets Etcd and there are no EtcdHosts, every master is a member.
+	Etcd bool `yaml:"etcd,omitempty"`
+}
+
+type EtcdHostDescription struct {
+	Hostname string   `yaml:"hostname"`
+	IPs      []string `yaml:"ips"`
+	// PrimaryIP defaults to the first of IPs.
+	PrimaryIP string `yaml:"primaryIP,omitempty"`
+}
+
+// MasterDescription describes the master components running on a node.
+type MasterDescription struct {
+	// Port is the port the master API listens on.
+	Port int16 `yaml:"port"`
+}
+
+type ManifestsDescription struct {
+	// List also writes a List combining every manifest.
+	List bool `yaml:"list,omitempty"`
+}
+
+// OutputDescription describes where generated files are written.  One
+// directory or archive is written per node, named after the node's hostname.
+type OutputDescription struct {
+	// Format defaults to OutputFormatDirectory.
+	Format string `yaml:"format,omitempty"`
+	// Gzip compresses the files in cloud-init output.
+	Gzip bool `yaml:"gzip,omitempty"`
+	// IgnitionVersion is 2 or 3 (the default).
+	IgnitionVersion int `yaml:"ignitionVersion,omitempty"`
+	// Reproducible archives depend only on the files they contain.
+	Reproducible bool `yaml:"reproducible,omitempty"`
+	// Path is the directory under which per-node output is written.  It
+	// defaults to the external master hostname.
+	Path      string                `yaml:"path,omitempty"`
+	Ownership *OwnershipDescription `yaml:"ownership,omitempty"`
+	// InPlace updates directory output in place rather than replacing it.
+	InPlace bool `yaml:"inPlace,omitempty"`
+	// Force is set by the -force flag rather than in the file.
+	Force bool `yaml:"-"`
+}
+
+type OwnershipDescription struct {
+	// Umask is octal and defaults to 0022.
+	Umask string `yaml:"umask,omitempty"`
+	// Later rules take precedence.
+	Rules []OwnershipRuleDescription `yaml:"rules,omitempty"`
+}
+
+// Modes are octal; user and group names are optional.
+type OwnershipRuleDescription struct {
+	Pattern string `yaml:"pattern"`
+	UID     *int   `yaml:"uid,omitempty"`
+	GID     *int   `yaml:"gid,omitempty"`
+	User    string `yaml:"user,omitempty"`
+	Group   string `yaml:"group,omitempty"`
+	Mode    string `yaml:"mode,omitempty"`
+	DirMode string `yaml:"dirMode,omitempty"`
+}
+
+// LoadClusterDescription reads and validates the cluster description in
+// filename.
+func LoadClusterDescription(filename string) (*ClusterDescription, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := ParseClusterDescription(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", filename, err)
+	}
+	d.dir = filepath.Dir(filename)
+
+	return d, nil
+}
+
+// ParseClusterDescription parses and validates a cluster description.  Unknown
+// keys and values of the wrong type are rejected with the offending line
+// number.
+func ParseClusterDescription(b []byte) (*ClusterDescription, error) {
+	var d *ClusterDescription
+	err := yaml.UnmarshalStrict(b, &d)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, fmt.Errorf("empty cluster description")
+	}
+
+	err = d.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// Validate checks that d is complete and self-consistent.  All problems found
+// are reported together.
+func (d *ClusterDescription) Validate() error {
+	var errs []string
+	errorf := func(format string, a ...interface{}) {
+		errs = append(errs, fmt.Sprintf(format, a...))
+	}
+
+	switch d.APIVersion {
+	case DescriptionAPIVersion:
+	case "":
+		errorf("apiVersion: must be set to %q", DescriptionAPIVersion)
+	default:
+		errorf("apiVersion: unsupported version %q (expected %q)", d.APIVersion, DescriptionAPIVersion)
+	}
+
+	if d.Kind != DescriptionKind {
+		errorf("kind: must be %q", DescriptionKind)
+	}
+
+	if d.ExternalMasterHostname == "" {
+		errorf("externalMasterHostname: must be set")
+	}
+
+	switch {
+	case d.ExternalRouterIP != "" && net.ParseIP(d.ExternalRouterIP) == nil:
+		errorf("externalRouterIP: invalid IP address %q", d.ExternalRouterIP)
+	case d.RouterSubdomain != "":
+		if !validDomain(d.RouterSubdomain) {
+			errorf("routerSubdomain: invalid domain %q", d.RouterSubdomain)
+		}
+	case d.ExternalRouterIP == "":
+		errorf("externalRouterIP: must be set unless routerSubdomain is set")
+	case net.ParseIP(d.ExternalRouterIP).To4() == nil:
+		errorf("externalRouterIP: nip.io requires an IPv4 address; set routerSubdomain instead")
+	}
+
+	serviceCIDR, clusterCIDR, hostSubnetLength, dnsDomain := d.network()
+	_, serviceNetwork, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		errorf("network.serviceCIDR: invalid CIDR %q", serviceCIDR)
+	} else if ones, bits := serviceNetwork.Mask.Size(); bits-ones < 2 {
+		errorf("network.serviceCIDR: %q is too small", serviceCIDR)
+	}
+	_, clusterNetwork, err := net.ParseCIDR(clusterCIDR)
+	if err != nil {
+		errorf("network.clusterCIDR: invalid CIDR %q", clusterCIDR)
+	} else if ones, bits := clusterNetwork.Mask.Size(); hostSubnetLength <= 0 || hostSubnetLength >= bits-ones {
+		errorf("network.hostSubnetLength: must be between 1 and %d for cluster CIDR %q", bits-ones-1, clusterCIDR)
+	}
+	if serviceNetwork != nil && clusterNetwork != nil &&
+		(serviceNetwork.Contains(clusterNetwork.IP) || clusterNetwork.Contains(serviceNetwork.IP)) {
+		errorf("network: serviceCIDR %q overlaps clusterCIDR %q", serviceCIDR, clusterCIDR)
+	}
+	if !validDomain(dnsDomain) {
+		errorf("network.dnsDomain: invalid domain %q", dnsDomain)
+	}
+
+	if d.Registry.ClusterIP != "" {
+		ip := net.ParseIP(d.Registry.ClusterIP)
+		switch {
+		case ip == nil:
+			errorf("registry.clusterIP: invalid IP address %q", d.Registry.ClusterIP)
+		case serviceNetwork != nil && !serviceNetwork.Contains(ip):
+			errorf("registry.clusterIP: %q is not in serviceCIDR %q", d.Registry.ClusterIP, serviceCIDR)
+		case serviceNetwork != nil && ip.Equal(serviceIP(serviceNetwork, 1)):
+			errorf("registry.clusterIP: %q is the kubernetes service's address", d.Registry.ClusterIP)
+		}
+	}
+	if d.Registry.Hostname != "" && !validDomain(d.Registry.Hostname) {
+		errorf("registry.hostname: invalid hostname %q", d.Registry.Hostname)
+	}
+
+	if len(d.Nodes) == 0 {
+		errorf("nodes: at least one node must be defined")
+	}
+
+	hostnames := map[string]int{}
+	master := -1
+	for i, node := range d.Nodes {
+		if node.Hostname == "" {
+			errorf("nodes[%d].hostname: must be set", i)
+		} else if j, exists := hostnames[node.Hostname]; exists {
+			errorf("nodes[%d].hostname: %q duplicates nodes[%d]", i, node.Hostname, j)
+		} else {
+			hostnames[node.Hostname] = i
+		}
+
+		if len(node.IPs) == 0 {
+			errorf("nodes[%d].ips: at least one IP address must be defined", i)
+		}
+		for j, ip := range node.IPs {
+			if net.ParseIP(ip) == nil {
+				errorf("nodes[%d].ips[%d]: invalid IP address %q", i, j, ip)
+			}
+		}
+		if node.PrimaryIP != "" && !containsIP(node.IPs, node.PrimaryIP) {
+			errorf("nodes[%d].primaryIP: %q is not one of the node's ips", i, node.PrimaryIP)
+		}
+
+		if node.Master != nil {
+			switch {
+			case node.Master.Port <= 0:
+				errorf("nodes[%d].master.port: must be between 1 and 32767", i)
+			case master == -1:
+				master = i
+			case node.Master.Port != d.Nodes[master].Master.Port:
+				errorf("nodes[%d].master.port: all masters must use the same port as nodes[%d]", i, master)
+			}
+		}
+	}
+
+	if len(d.Nodes) > 0 && master == -1 {
+		errorf("nodes: at least one master must be defined")
+	}
+
+	for i, host := range d.EtcdHosts {
+		if host.Hostname == "" {
+			errorf("etcdHosts[%d].hostname: must be set", i)
+		} else if _, exists := hostnames[host.Hostname]; exists {
+			errorf("etcdHosts[%d].hostname: %q duplicates another host", i, host.Hostname)
+		} else {
+			hostnames[host.Hostname] = -1
+		}
+
+		if len(host.IPs) == 0 {
+			errorf("etcdHosts[%d].ips: at least one IP address must be defined", i)
+		}
+		for j, ip := range host.IPs {
+			if net.ParseIP(ip) == nil {
+				errorf("etcdHosts[%d].ips[%d]: invalid IP address %q", i, j, ip)
+			}
+		}
+		if host.PrimaryIP != "" && !containsIP(host.IPs, host.PrimaryIP) {
+			errorf("etcdHosts[%d].primaryIP: %q is not one of the host's ips", i, host.PrimaryIP)
+		}
+	}
+
+	names := make([]string, 0, len(d.CAs))
+	for name := range d.CAs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		var known bool
+		for _, caName := range CANames {
+			known = known || name == caName
+		}
+		if !known {
+			errorf("cas.%s: unknown CA (expected one of %s)", name, strings.Join(CANames, ", "))
+		}
+		if d.CAs[name].Cert == "" {
+			errorf("cas.%s.cert: must be set", name)
+		}
+		if d.CAs[name].Key == "" {
+			errorf("cas.%s.key: must be set", name)
+		}
+	}
+
+	if d.RootCA != nil && d.RootCA.Cert == "" {
+		errorf("rootCA.cert: must be set")
+	}
+
+	if nc := d.NamedCertificate; nc != nil {
+		switch {
+		case nc.Generate && (nc.Cert != "" || nc.Key != "" || nc.CA != ""):
+			errorf("namedCertificate: cert, key and ca must not be set with generate")
+		case !nc.Generate:
+			if nc.Cert == "" {
+				errorf("namedCertificate.cert: must be set unless generate is set")
+			}
+			if nc.Key == "" {
+				errorf("namedCertificate.key: must be set unless generate is set")
+			}
+			if nc.CA == "" {
+				errorf("namedCertificate.ca: must be set unless generate is set")
+			}
+		}
+	}
+
+	classes := make([]string, 0, len(d.Keys))
+	for class := range d.Keys {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		err := KeySpec{Algorithm: d.Keys[class].Algorithm, Encoding: d.Keys[class].Encoding}.Validate(class)
+		if err != nil {
+			errorf("keys.%s: %v", class, err)
+		}
+	}
+
+	components := make([]string, 0, len(d.Components))
+	for name := range d.Components {
+		components = append(components, name)
+	}
+	sort.Strings(components)
+	for _, name := range components {
+		if _, found := componentCertNames[name]; !found {
+			errorf("components.%s: unknown component (expected one of %s)", name, strings.Join(ComponentNames, ", "))
+		}
+		switch d.Components[name].CA {
+		case "", "main", "dedicated":
+		default:
+			errorf("components.%s.ca: must be \"main\" or \"dedicated\"", name)
+		}
+	}
+
+	certNames := d.certNames()
+	patterns := make([]string, 0, len(d.Profiles))
+	for pattern := range d.Profiles {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			errorf("profiles.%s: invalid pattern", pattern)
+			continue
+		}
+		var matched bool
+		for _, name := range certNames {
+			if m, _ := path.Match(pattern, name); m {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			errorf("profiles.%s: matches no certificate", pattern)
+		}
+
+		_, err := d.Profiles[pattern].profile()
+		if err != nil {
+			errorf("profiles.%s.%v", pattern, err)
+		}
+	}
+
+	if d.Output.Format != "" {
+		var known bool
+		for _, format := range OutputFormats {
+			known = known || d.Output.Format == format
+		}
+		if !known {
+			errorf("output.format: unknown format %q (expected one of %s)", d.Output.Format, strings.Join(OutputFormats, ", "))
+		}
+	}
+	if d.Output.Gzip && d.OutputFormat() != OutputFormatCloudInit {
+		errorf("output.gzip: only supported by format %q", OutputFormatCloudInit)
+	}
+	switch {
+	case d.Output.IgnitionVersion != 0 && d.OutputFormat() != OutputFormatIgnition:
+		errorf("output.ignitionVersion: only supported by format %q", OutputFormatIgnition)
+	case d.Output.IgnitionVersion != 0 && d.Output.IgnitionVersion != 2 && d.Output.IgnitionVersion != 3:
+		errorf("output.ignitionVersion: must be 2 or 3")
+	}
+	if d.Output.Reproducible && d.OutputFormat() == OutputFormatDirectory {
+		errorf("output.reproducible: not supported by format %q", OutputFormatDirectory)
+	}
+	if d.Output.InPlace && d.OutputFormat() != OutputFormatDirectory {
+		errorf("output.inPlace: only supported by format %q", OutputFormatDirectory)
+	}
+	if d.Output.Ownership != nil {
+		_, err := d.Output.Ownership.policy()
+		if err != nil {
+			errorf("output.ownership.%v", err)
+		}
+		for i, rule := range d.Output.Ownership.Rules {
+			if rule.DirMode != "" && d.OutputFormat() == OutputFormatCloudInit {
+				errorf("output.ownership.rules[%d].dirMode: not supported by format %q", i, OutputFormatCloudInit)
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid cluster description:\n  %s", strings.Join(errs, "\n  "))
+	}
+
+	return nil
+}
+
+func (d *ClusterDescription) certNames() []string {
+	names := append([]string{"etcd/peer", "etcd/server", "node/server"}, masterCertNames...)
+	for _, node := range d.Nodes {
+		names = append(names, fmt.Sprintf("system:node:%s", node.Hostname))
+	}
+	for name := range d.Components {
+		names = append(names, componentCertNames[name]...)
+	}
+	if d.NamedCertificate != nil && d.NamedCertificate.Generate {
+		names = append(names, "named")
+	}
+	return names
+}
+
+func (p ProfileDescription) profile() (Profile, error) {
+	var profile Profile
+	var err error
+
+	if p.Validity != "" {
+		profile.Validity, err = ParseValidity(p.Validity)
+		if err != nil {
+			return Profile{}, fmt.Errorf("validity: %v", err)
+		}
+		if profile.Validity <= 0 {
+			return Profile{}, fmt.Errorf("validity: must be positive")
+		}
+	}
+
+	for _, name := range p.KeyUsages {
+		usage, found := KeyUsageNames[name]
+		if !found {
+			return Profile{}, fmt.Errorf("keyUsages: unknown key usage %q", name)
+		}
+		profile.KeyUsage |= usage
+	}
+
+	for _, name := range p.ExtKeyUsages {
+		usage, found := ExtKeyUsageNames[name]
+		if !found {
+			return Profile{}, fmt.Errorf("extKeyUsages: unknown extended key usage %q", name)
+		}
+		profile.ExtKeyUsage = append(profile.ExtKeyUsage, usage)
+	}
+
+	profile.ExtraDNSNames = p.ExtraDNSNames
+
+	for _, ip := range p.ExtraIPs {
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return Profile{}, fmt.Errorf("extraIPs: invalid IP address %q", ip)
+		}
+		profile.ExtraIPs = append(profile.ExtraIPs, parsed)
+	}
+
+	if p.Subject != nil {
+		profile.CommonName = p.Subject.CommonName
+		profile.Organizations = p.Subject.Organizations
+	}
+
+	return profile, nil
+}
+
+func parseMode(s string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(s, 8, 32)
+	if err != nil || mode&^uint64(os.ModePerm) != 0 {
+		return 0, fmt.Errorf("invalid mode %q", s)
+	}
+	return os.FileMode(mode), nil
+}
+
+func (o *OwnershipDescription) policy() (*filesystem.Policy, error) {
+	policy := &filesystem.Policy{Umask: 0022}
+	var err error
+
+	if o.Umask != "" {
+		policy.Umask, err = parseMode(o.Umask)
+		if err != nil {
+			return nil, fmt.Errorf("umask: %v", err)
+		}
+	}
+
+	for i, r := range o.Rules {
+		rule := filesystem.PolicyRule{Pattern: r.Pattern}
+
+		if r.Pattern == "" {
+			return nil, fmt.Errorf("rules[%d].pattern: must be set", i)
+		}
+		_, err = path.Match(r.Pattern, "")
+		if err != nil {
+			return nil, fmt.Errorf("rules[%d].pattern: %v", i, err)
+		}
+
+		switch {
+		case r.UID != nil && r.GID != nil:
+			rule.Owner = &filesystem.Owner{UID: *r.UID, GID: *r.GID, User: r.User, Group: r.Group}
+		case r.UID != nil || r.GID != nil || r.User != "" || r.Group != "":
+			return nil, fmt.Errorf("rules[%d]: an owner must be given by uid and gid", i)
+		}
+
+		if r.Mode != "" {
+			rule.Mode, err = parseMode(r.Mode)
+			if err != nil {
+				return nil, fmt.Errorf("rules[%d].mode: %v", i, err)
+			}
+		}
+
+		if r.DirMode != "" {
+			rule.DirMode, err = parseMode(r.DirMode)
+			if err != nil {
+				return nil, fmt.Errorf("rules[%d].dirMode: %v", i, err)
+			}
+		}
+
+		policy.Rules = append(policy.Rules, rule)
+	}
+
+	return policy, nil
+}
+
+func (d *ClusterDescription) network() (serviceCIDR, clusterCIDR string, hostSubnetLength int, dnsDomain string) {
+	serviceCIDR, clusterCIDR = d.Network.ServiceCIDR, d.Network.ClusterCIDR
+	hostSubnetLength, dnsDomain = d.Network.HostSubnetLength, d.Network.DNSDomain
+
+	if serviceCIDR == "" {
+		serviceCIDR = DefaultServiceCIDR
+	}
+	if clusterCIDR == "" {
+		clusterCIDR = DefaultClusterCIDR
+	}
+	if hostSubnetLength == 0 {
+		hostSubnetLength = DefaultHostSubnetLength
+	}
+	if dnsDomain == "" {
+		dnsDomain = DefaultDNSDomain
+	}
+
+	return
+}
+
+// OutputFormat returns the output format, applying the default.
+func (d *ClusterDescription) OutputFormat() string {
+	if d.Output.Format == "" {
+		return OutputFormatDirectory
+	}
+	return d.Output.Format
+}
+
+func (d *ClusterDescription) IgnitionVersion() string {
+	if d.Output.IgnitionVersion == 2 {
+		return filesystem.IgnitionV2
+	}
+	return filesystem.IgnitionV3
+}
+
+func (d *ClusterDescription) OutputPolicy() *filesystem.Policy {
+	if d.Output.Ownership == nil {
+		return nil
+	}
+	policy, _ := d.Output.Ownership.policy()
+	return policy
+}
+
+// OutputPath returns the output path, applying the default.
+func (d *ClusterDescription) OutputPath() string {
+	if d.Output.Path == "" {
+		return d.ExternalMasterHostname
+	}
+	return d.Output.Path
+}
+
+func (d *ClusterDescription) path(filename string) string {
+	if filepath.IsAbs(filename) {
+		return filename
+	}
+	return filepath.Join(d.dir, filename)
+}
+
+// Config reads and checks any existing CAs; d must be valid.
+func (d *ClusterDescription) Config() (*Config, error) {
+	serviceCIDR, clusterCIDR, hostSubnetLength, dnsDomain := d.network()
+	_, serviceNetwork, err := net.ParseCIDR(serviceCIDR)
+	if err != nil {
+		return nil, err
+	}
+	_, clusterNetwork, err := net.ParseCIDR(clusterCIDR)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{
+		ExternalMasterHostname: d.ExternalMasterHostname,
+		ExternalRouterIP:       net.ParseIP(d.ExternalRouterIP),
+		RouterSubdomain:        d.RouterSubdomain,
+		ServiceNetwork:         serviceNetwork,
+		ClusterNetwork:         clusterNetwork,
+		HostSubnetLength:       hostSubnetLength,
+		DNSDomain:              dnsDomain,
+		RegistryHostname:       d.Registry.Hostname,
+		EmitManifests:          d.Manifests != nil,
+		EmitManifestList:       d.Manifests != nil && d.Manifests.List,
+	}
+	if d.Registry.ClusterIP != "" {
+		c.RegistryClusterIP = net.ParseIP(d.Registry.ClusterIP)
+	}
+
+	// if no etcd members are declared, etcd is co-located on the masters
+	etcdDeclared := len(d.EtcdHosts) > 0
+	for _, nd := range d.Nodes {
+		etcdDeclared = etcdDeclared || nd.Etcd
+	}
+
+	for _, nd := range d.Nodes {
+		node := Node{
+			Hostname: nd.Hostname,
+			IPs:      parseIPs(nd.IPs, nd.PrimaryIP),
+		}
+		if nd.Master != nil {
+			node.Master = &Master{
+				Port: nd.Master.Port,
+			}
+		}
+		if nd.Etcd || !etcdDeclared && nd.Master != nil {
+			node.Etcd = &Etcd{}
+		}
+		c.Nodes = append(c.Nodes, node)
+	}
+
+	for _, host := range d.EtcdHosts {
+		c.EtcdHosts = append(c.EtcdHosts, Node{
+			Hostname: host.Hostname,
+			IPs:      parseIPs(host.IPs, host.PrimaryIP),
+			Etcd:     &Etcd{},
+		})
+	}
+
+	for class, key := range d.Keys {
+		err := c.SetKeySpec(class, KeySpec{Algorithm: key.Algorithm, Encoding: key.Encoding})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for name, component := range d.Components {
+		err := c.EnableComponent(name, component.CA == "dedicated")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for pattern, pd := range d.Profiles {
+		profile, err := pd.profile()
+		if err != nil {
+			return nil, fmt.Errorf("profiles.%s.%v", pattern, err)
+		}
+
+		err = c.SetProfile(pattern, profile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if d.RootCA != nil {
+		cert, err := ioutil.ReadFile(d.path(d.RootCA.Cert))
+		if err != nil {
+			return nil, err
+		}
+
+		var key []byte
+		if d.RootCA.Key != "" {
+			key, err = ioutil.ReadFile(d.path(d.RootCA.Key))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		err = c.SetRootCA(cert, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if nc := d.NamedCertificate; nc != nil && nc.Generate {
+		c.GenerateNamedCertificate()
+	} else if nc != nil {
+		cert, err := ioutil.ReadFile(d.path(nc.Cert))
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ioutil.ReadFile(d.path(nc.Key))
+		if err != nil {
+			return nil, err
+		}
+
+		ca, err := ioutil.ReadFile(d.path(nc.CA))
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.SetNamedCertificate(cert, key, ca)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range CANames {
+		ca, exists := d.CAs[name]
+		if !exists {
+			continue
+		}
+
+		cert, err := ioutil.ReadFile(d.path(ca.Cert))
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ioutil.ReadFile(d.path(ca.Key))
+		if err != nil {
+			return nil, err
+		}
+
+		err = c.SetCA(name, cert, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// parseIPs moves primary, if set, to the front, as a Node's primary address is
+// its first.
+func parseIPs(ips []string, primary string) []net.IP {
+	parsed := make([]net.IP, 0, len(ips))
+	if primary != "" {
+		parsed = append(parsed, net.ParseIP(primary))
+	}
+	for _, ip := range ips {
+		if primary == "" || !net.ParseIP(ip).Equal(parsed[0]) {
+			parsed = append(parsed, net.ParseIP(ip))
+		}
+	}
+	return parsed
+}
+
+// validDomain requires non-empty labels and no wildcards.
+func validDomain(domain string) bool {
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || strings.ContainsAny(label, "*/: ") {
+			return false
+		}
+	}
+	return true
+}
+
+// containsIP compares addresses rather than strings.
+func containsIP(ips []string, ip string) bool {
+	parsed := net.ParseIP(ip)
+	for _, candidate := range ips {
+		if parsed != nil && parsed.Equal(net.ParseIP(candidate)) {
+			return true
+		}
+	}
+	return false
+}
@@ -4,7 +4,10 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/base64"
+	"fmt"
+	"net"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -12,7 +15,17 @@ import (
 	"github.com/jim-minter/certgen/pkg/filesystem"
 )
 
+type templateData struct {
+	*Config
+	Node *Node
+}
+
 func (c *Config) PrepareMasterFiles(node *Node) error {
+	if c.AuthSecret != "" && c.EncSecret != "" {
+		// the session secrets are shared by all masters
+		return nil
+	}
+
 	b := make([]byte, 24)
 	_, err := rand.Read(b)
 	if err != nil {
@@ -30,55 +43,54 @@ func (c *Config) PrepareMasterFiles(node *Node) error {
 }
 
 func (c *Config) WriteMasterFiles(fs filesystem.Filesystem, node *Node) error {
-	for _, name := range templates.AssetNames() {
-		if !strings.HasPrefix(name, "master/") {
-			continue
-		}
-		tb := templates.MustAsset(name)
+	return writeTemplates(fs, "master/", &templateData{Config: c, Node: node})
+}
 
-		t, err := template.New("template").Funcs(template.FuncMap{
-			"QuoteMeta": regexp.QuoteMeta,
-		}).Parse(string(tb))
-		if err != nil {
-			return err
-		}
+func (c *Config) WriteEtcdFiles(fs filesystem.Filesystem, node *Node) error {
+	return writeTemplates(fs, "etcd/", &templateData{Config: c, Node: node})
+}
 
-		b := &bytes.Buffer{}
-		err = t.Execute(b, c)
-		if err != nil {
-			return err
-		}
+func (c *Config) WriteNodeFiles(fs filesystem.Filesystem, node *Node) error {
+	return writeTemplates(fs, "node/", &templateData{Config: c, Node: node})
+}
 
-		err = fs.WriteFile(strings.TrimPrefix(name, "master/"), b.Bytes(), 0666)
-		if err != nil {
-			return err
-		}
-	}
+// hostPort joins host and port as in a URL, bracketing IPv6 addresses.
+func hostPort(host interface{}, port int) string {
+	return net.JoinHostPort(fmt.Sprint(host), strconv.Itoa(port))
+}
 
-	return nil
+// urlHost returns host as in a URL, bracketing IPv6 addresses.
+func urlHost(host interface{}) string {
+	s := fmt.Sprint(host)
+	if strings.Contains(s, ":") {
+		return "[" + s + "]"
+	}
+	return s
 }
 
-func (c *Config) WriteNodeFiles(fs filesystem.Filesystem, node *Node) error {
+func writeTemplates(fs filesystem.Filesystem, prefix string, data interface{}) error {
 	for _, name := range templates.AssetNames() {
-		if !strings.HasPrefix(name, "node/") {
+		if !strings.HasPrefix(name, prefix) {
 			continue
 		}
 		tb := templates.MustAsset(name)
 
 		t, err := template.New("template").Funcs(template.FuncMap{
 			"QuoteMeta": regexp.QuoteMeta,
+			"HostPort":  hostPort,
+			"URLHost":   urlHost,
 		}).Parse(string(tb))
 		if err != nil {
 			return err
 		}
 
 		b := &bytes.Buffer{}
-		err = t.Execute(b, node)
+		err = t.Execute(b, data)
 		if err != nil {
 			return err
 		}
 
-		err = fs.WriteFile(strings.TrimPrefix(name, "node/"), b.Bytes(), 0666)
+		err = fs.WriteFile(strings.TrimPrefix(name, prefix), b.Bytes(), 0666)
 		if err != nil {
 			return err
 		}
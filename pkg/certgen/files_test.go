@@ -0,0 +1,78 @@
+package certgen
+
+import (
+	"net"
+	"testing"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConfigFilesParse(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		ips      string
+		bindHost string
+	}{
+		{name: "ipv4", ips: `[10.0.0.1]`, bindHost: "0.0.0.0"},
+		{name: "ipv6", ips: `["fd00::1"]`, bindHost: "::"},
+		{name: "dual-stack", ips: `["fd00::1", 10.0.0.1]`, bindHost: "::"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := ParseClusterDescription([]byte(`
+apiVersion: certgen/v1
+kind: ClusterDescription
+externalMasterHostname: master.example.com
+externalRouterIP: 10.0.0.100
+nodes:
+- hostname: master1
+  ips: ` + tt.ips + `
+  master: {port: 8443}
+`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := d.Config()
+			if err != nil {
+				t.Fatal(err)
+			}
+			node := &c.Nodes[0]
+
+			fs := filesystem.NewMemoryFilesystem()
+			err = c.WriteMasterFiles(fs, node)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = c.WriteNodeFiles(fs, node)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for filename, port := range map[string]string{
+				"etc/origin/master/master-config.yaml": "8443",
+				"etc/origin/node/node-config.yaml":     "10250",
+			} {
+				b, err := fs.ReadFile(filename)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var config struct {
+					ServingInfo struct {
+						BindAddress string `yaml:"bindAddress"`
+					} `yaml:"servingInfo"`
+				}
+				err = yaml.Unmarshal(b, &config)
+				if err != nil {
+					t.Fatalf("%s: %v", filename, err)
+				}
+
+				bindAddress := net.JoinHostPort(tt.bindHost, port)
+				if config.ServingInfo.BindAddress != bindAddress {
+					t.Errorf("%s: servingInfo.bindAddress = %q, want %q", filename, config.ServingInfo.BindAddress, bindAddress)
+				}
+			}
+		})
+	}
+}
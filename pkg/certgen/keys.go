@@ -0,0 +1,217 @@
+package certgen
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+// Key algorithms.
+const (
+	KeyAlgorithmRSA2048   = "rsa-2048"
+	KeyAlgorithmRSA3072   = "rsa-3072"
+	KeyAlgorithmRSA4096   = "rsa-4096"
+	KeyAlgorithmECDSAP256 = "ecdsa-p256"
+	KeyAlgorithmECDSAP384 = "ecdsa-p384"
+)
+
+var KeyAlgorithms = []string{
+	KeyAlgorithmRSA2048, KeyAlgorithmRSA3072, KeyAlgorithmRSA4096,
+	KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384,
+}
+
+// Private key encodings.
+const (
+	KeyEncodingPKCS1 = "pkcs1"
+	KeyEncodingSEC1  = "sec1"
+	KeyEncodingPKCS8 = "pkcs8"
+)
+
+// Key classes group the certificates whose keys share a KeySpec.
+const (
+	// KeyClassCA also covers the CAs of optional components.
+	KeyClassCA     = "ca"
+	KeyClassEtcdCA = "etcd-ca"
+	KeyClassMaster = "master"
+	KeyClassEtcd   = "etcd"
+	KeyClassNode   = "node"
+	// KeyClassRouter covers the router's certificate and a generated named
+	// certificate, which are presented to browsers.
+	KeyClassRouter    = "router"
+	KeyClassComponent = "component"
+)
+
+var KeyClasses = []string{KeyClassCA, KeyClassEtcdCA, KeyClassMaster, KeyClassEtcd, KeyClassNode, KeyClassRouter, KeyClassComponent}
+
+type KeySpec struct {
+	Algorithm string
+	// Encoding is empty for the algorithm's default.
+	Encoding string
+}
+
+func (s KeySpec) Validate(class string) error {
+	var known bool
+	for _, c := range KeyClasses {
+		known = known || class == c
+	}
+	if !known {
+		return fmt.Errorf("unknown key class %q (expected one of %s)", class, strings.Join(KeyClasses, ", "))
+	}
+
+	switch s.Algorithm {
+	case KeyAlgorithmRSA2048, KeyAlgorithmRSA3072, KeyAlgorithmRSA4096:
+		if s.Encoding != "" && s.Encoding != KeyEncodingPKCS1 && s.Encoding != KeyEncodingPKCS8 {
+			return fmt.Errorf("RSA keys must be encoded as %s or %s", KeyEncodingPKCS1, KeyEncodingPKCS8)
+		}
+	case KeyAlgorithmECDSAP256, KeyAlgorithmECDSAP384:
+		if s.Encoding != "" && s.Encoding != KeyEncodingSEC1 && s.Encoding != KeyEncodingPKCS8 {
+			return fmt.Errorf("ECDSA keys must be encoded as %s or %s", KeyEncodingSEC1, KeyEncodingPKCS8)
+		}
+	default:
+		return fmt.Errorf("unknown key algorithm %q (expected one of %s)", s.Algorithm, strings.Join(KeyAlgorithms, ", "))
+	}
+
+	return nil
+}
+
+// Classes which are not configured use PKCS#1 encoded RSA 2048 keys (RSA 4096
+// for the etcd CA).
+func (c *Config) SetKeySpec(class string, spec KeySpec) error {
+	err := spec.Validate(class)
+	if err != nil {
+		return err
+	}
+
+	if c.keySpecs == nil {
+		c.keySpecs = map[string]KeySpec{}
+	}
+	c.keySpecs[class] = spec
+
+	return nil
+}
+
+func (c *Config) keySpec(class string) KeySpec {
+	if spec, found := c.keySpecs[class]; found {
+		return spec
+	}
+	if class == KeyClassEtcdCA {
+		return KeySpec{Algorithm: KeyAlgorithmRSA4096}
+	}
+	return KeySpec{Algorithm: KeyAlgorithmRSA2048}
+}
+
+func caKeyClass(name string) string {
+	if name == "master.etcd-ca" {
+		return KeyClassEtcdCA
+	}
+	return KeyClassCA
+}
+
+func generateKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case KeyAlgorithmRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeyAlgorithmRSA3072:
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case KeyAlgorithmRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case KeyAlgorithmECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeyAlgorithmECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	}
+	return nil, fmt.Errorf("unknown key algorithm %q", algorithm)
+}
+
+func keyAlgorithm(pub crypto.PublicKey) string {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		switch pub.N.BitLen() {
+		case 2048:
+			return KeyAlgorithmRSA2048
+		case 3072:
+			return KeyAlgorithmRSA3072
+		case 4096:
+			return KeyAlgorithmRSA4096
+		}
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return KeyAlgorithmECDSAP256
+		case elliptic.P384():
+			return KeyAlgorithmECDSAP384
+		}
+	}
+	return ""
+}
+
+func keyMatches(pub crypto.PublicKey, key crypto.Signer) bool {
+	a, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return false
+	}
+
+	b, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(a, b)
+}
+
+// For RSA keys, keyId is the SHA-1 hash of the modulus; for other keys, of the
+// encoded public key (RFC 5280 section 4.2.1.2).
+func keyId(pub crypto.PublicKey) ([]byte, error) {
+	if pub, ok := pub.(*rsa.PublicKey); ok {
+		return intsha1(pub.N), nil
+	}
+
+	b, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	var spki struct {
+		Algorithm asn1.RawValue
+		PublicKey asn1.BitString
+	}
+	_, err = asn1.Unmarshal(b, &spki)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha1.Sum(spki.PublicKey.Bytes)
+	return h[:], nil
+}
+
+func privateKeyBlock(key crypto.Signer, encoding string) (*pem.Block, error) {
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		if encoding == "" || encoding == KeyEncodingPKCS1 {
+			return &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}, nil
+		}
+	case *ecdsa.PrivateKey:
+		if encoding == "" || encoding == KeyEncodingSEC1 {
+			b, err := x509.MarshalECPrivateKey(key)
+			if err != nil {
+				return nil, err
+			}
+			return &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}, nil
+		}
+	}
+
+	b, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &pem.Block{Type: "PRIVATE KEY", Bytes: b}, nil
+}
@@ -60,7 +60,11 @@ func (c *Config) PrepareMasterKubeConfigs(node *Node) error {
 	localhostEndpoint := fmt.Sprintf("localhost:%d", node.Master.Port)
 	localhostEndpointName := strings.Replace(localhostEndpoint, ".", "-", -1)
 
-	cacert, err := certAsBytes(c.cas["ca"].cert)
+	cacert, err := certAsBytes(c.caBundle("ca")...)
+	if err != nil {
+		return err
+	}
+	externalcacert, err := certAsBytes(c.externalCABundle()...)
 	if err != nil {
 		return err
 	}
@@ -68,7 +72,7 @@ func (c *Config) PrepareMasterKubeConfigs(node *Node) error {
 	if err != nil {
 		return err
 	}
-	adminkey, err := privateKeyAsBytes(node.Master.certs["admin"].key)
+	adminkey, err := privateKeyAsBytes(node.Master.certs["admin"].key, c.keySpec(KeyClassMaster).Encoding)
 	if err != nil {
 		return err
 	}
@@ -76,7 +80,7 @@ func (c *Config) PrepareMasterKubeConfigs(node *Node) error {
 	if err != nil {
 		return err
 	}
-	masterkey, err := privateKeyAsBytes(node.Master.certs["openshift-master"].key)
+	masterkey, err := privateKeyAsBytes(node.Master.certs["openshift-master"].key, c.keySpec(KeyClassMaster).Encoding)
 	if err != nil {
 		return err
 	}
@@ -84,7 +88,15 @@ func (c *Config) PrepareMasterKubeConfigs(node *Node) error {
 	if err != nil {
 		return err
 	}
-	aggregatorkey, err := privateKeyAsBytes(node.Master.certs["aggregator-front-proxy"].key)
+	aggregatorkey, err := privateKeyAsBytes(node.Master.certs["aggregator-front-proxy"].key, c.keySpec(KeyClassMaster).Encoding)
+	if err != nil {
+		return err
+	}
+	registrycert, err := certAsBytes(node.Master.certs["openshift-registry"].cert)
+	if err != nil {
+		return err
+	}
+	registrykey, err := privateKeyAsBytes(node.Master.certs["openshift-registry"].key, c.keySpec(KeyClassMaster).Encoding)
 	if err != nil {
 		return err
 	}
@@ -98,7 +110,7 @@ func (c *Config) PrepareMasterKubeConfigs(node *Node) error {
 					Name: externalEndpointName,
 					Cluster: ClusterInfo{
 						Server: fmt.Sprintf("https://%s", externalEndpoint),
-						CertificateAuthorityData: base64.StdEncoding.EncodeToString(cacert),
+						CertificateAuthorityData: base64.StdEncoding.EncodeToString(externalcacert),
 					},
 				},
 			},
@@ -189,16 +201,49 @@ func (c *Config) PrepareMasterKubeConfigs(node *Node) error {
 				},
 			},
 		},
+		"openshift-registry.kubeconfig": KubeConfig{
+			APIVersion: "v1",
+			Kind:       "Config",
+			Clusters: []Cluster{
+				{
+					Name: externalEndpointName,
+					Cluster: ClusterInfo{
+						Server: fmt.Sprintf("https://%s", externalEndpoint),
+						CertificateAuthorityData: base64.StdEncoding.EncodeToString(externalcacert),
+					},
+				},
+			},
+			Contexts: []Context{
+				{
+					Name: fmt.Sprintf("default/%s/system:openshift-registry", externalEndpointName),
+					Context: ContextInfo{
+						Cluster:   externalEndpointName,
+						Namespace: "default",
+						User:      fmt.Sprintf("system:openshift-registry/%s", externalEndpointName),
+					},
+				},
+			},
+			CurrentContext: fmt.Sprintf("default/%s/system:openshift-registry", externalEndpointName),
+			Users: []User{
+				{
+					Name: fmt.Sprintf("system:openshift-registry/%s", externalEndpointName),
+					User: UserInfo{
+						ClientCertificateData: base64.StdEncoding.EncodeToString(registrycert),
+						ClientKeyData:         base64.StdEncoding.EncodeToString(registrykey),
+					},
+				},
+			},
+		},
 	}
 
 	return nil
 }
 
 func (c *Config) PrepareNodeKubeConfig(node *Node) error {
-	ep := fmt.Sprintf("%s:%d", c.ExternalMasterHostname, c.Nodes[0].Master.Port)
+	ep := fmt.Sprintf("%s:%d", c.ExternalMasterHostname, c.Masters()[0].Master.Port)
 	epName := strings.Replace(ep, ".", "-", -1)
 
-	cacert, err := certAsBytes(c.cas["ca"].cert)
+	cacert, err := certAsBytes(c.externalCABundle()...)
 	if err != nil {
 		return err
 	}
@@ -206,7 +251,7 @@ func (c *Config) PrepareNodeKubeConfig(node *Node) error {
 	if err != nil {
 		return err
 	}
-	masterclientkey, err := privateKeyAsBytes(node.certs[fmt.Sprintf("system:node:%s", node.Hostname)].key)
+	masterclientkey, err := privateKeyAsBytes(node.certs[fmt.Sprintf("system:node:%s", node.Hostname)].key, c.keySpec(KeyClassNode).Encoding)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,246 @@
+package certgen
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+	"gopkg.in/yaml.v2"
+)
+
+// Load reads back a tree previously written for node, and must be called before
+// PrepareCAs.  Leaf certificates are reused by the Prepare methods if reusable.
+func (c *Config) Load(r filesystem.Reader, node *Node) error {
+	files, err := r.Files()
+	if err != nil {
+		return err
+	}
+
+	exists := map[string]bool{}
+	for _, filename := range files {
+		exists[filename] = true
+	}
+
+	for _, name := range CANames {
+		certfile, keyfile := fmt.Sprintf("etc/origin/master/%s.crt", name), fmt.Sprintf("etc/origin/master/%s.key", name)
+		if _, set := c.cas[name]; set || !exists[certfile] || !exists[keyfile] {
+			continue
+		}
+
+		certPEM, err := r.ReadFile(certfile)
+		if err != nil {
+			return err
+		}
+
+		keyPEM, err := r.ReadFile(keyfile)
+		if err != nil {
+			return err
+		}
+
+		ca, err := parseCA(name, certPEM, keyPEM)
+		if err != nil {
+			return err
+		}
+
+		if c.cas == nil {
+			c.cas = map[string]CertAndKey{}
+		}
+		c.cas[name] = ca
+		c.serial.Observe(ca.cert.SerialNumber)
+	}
+
+	err = c.loadRotatingCAs(r, exists)
+	if err != nil {
+		return err
+	}
+
+	if c.serviceAccountKey == nil && exists["etc/origin/master/serviceaccounts.private.key"] {
+		b, err := r.ReadFile("etc/origin/master/serviceaccounts.private.key")
+		if err != nil {
+			return err
+		}
+
+		key, err := ParsePrivateKey(b)
+		if err != nil {
+			return fmt.Errorf("etc/origin/master/serviceaccounts.private.key: %v", err)
+		}
+
+		if key, ok := key.(*rsa.PrivateKey); ok {
+			c.serviceAccountKey = key
+		}
+	}
+
+	if (c.AuthSecret == "" || c.EncSecret == "") && exists["etc/origin/master/session-secrets.yaml"] {
+		b, err := r.ReadFile("etc/origin/master/session-secrets.yaml")
+		if err != nil {
+			return err
+		}
+
+		var secrets struct {
+			Secrets []struct {
+				Authentication string `yaml:"authentication"`
+				Encryption     string `yaml:"encryption"`
+			} `yaml:"secrets"`
+		}
+		err = yaml.Unmarshal(b, &secrets)
+		if err != nil {
+			return fmt.Errorf("etc/origin/master/session-secrets.yaml: %v", err)
+		}
+
+		if len(secrets.Secrets) > 0 && secrets.Secrets[0].Authentication != "" && secrets.Secrets[0].Encryption != "" {
+			c.AuthSecret = secrets.Secrets[0].Authentication
+			c.EncSecret = secrets.Secrets[0].Encryption
+		}
+	}
+
+	if node.Master != nil {
+		node.Master.existing, err = c.loadCerts(r, exists, "etc/origin/master/")
+		if err != nil {
+			return err
+		}
+
+		// the router's and registry's certificates are shared by the masters
+		if c.existingClusterCerts == nil {
+			c.existingClusterCerts = map[string]CertAndKey{}
+			for _, filename := range clusterCertNames {
+				if existing, found := node.Master.existing[filename]; found {
+					c.existingClusterCerts[filename] = existing
+				}
+			}
+		}
+		for _, filename := range clusterCertNames {
+			delete(node.Master.existing, filename)
+		}
+
+		// as are component certificates
+		if c.existingComponentCerts == nil {
+			c.existingComponentCerts, err = c.loadComponentSecrets(r, exists)
+			if err != nil {
+				return err
+			}
+		}
+
+		// and the named certificate; a supplied one is not reused
+		if c.named != nil && c.named.generate && c.named.existing == nil {
+			c.named.existing, err = c.loadCerts(r, exists, "etc/origin/master/named_certificates/")
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if node.Etcd != nil {
+		node.Etcd.existing, err = c.loadCerts(r, exists, "etc/etcd/")
+		if err != nil {
+			return err
+		}
+	}
+
+	node.existing, err = c.loadCerts(r, exists, "etc/origin/node/")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Certificates whose key is missing or unreadable are ignored.
+func (c *Config) loadCerts(r filesystem.Reader, exists map[string]bool, prefix string) (map[string]CertAndKey, error) {
+	certs := map[string]CertAndKey{}
+
+	for filename := range exists {
+		if !strings.HasPrefix(filename, prefix) || !strings.HasSuffix(filename, ".crt") ||
+			strings.Contains(strings.TrimPrefix(filename, prefix), "/") {
+			continue
+		}
+		keyfile := strings.TrimSuffix(filename, ".crt") + ".key"
+		if !exists[keyfile] {
+			continue
+		}
+
+		b, err := r.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := ParseCertificates(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", filename, err)
+		}
+		cert := parsed[0]
+		c.serial.Observe(cert.SerialNumber)
+
+		if cert.IsCA {
+			continue
+		}
+
+		b, err = r.ReadFile(keyfile)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := ParsePrivateKey(b)
+		if err != nil {
+			continue
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			continue
+		}
+
+		certs[strings.TrimSuffix(strings.TrimPrefix(filename, prefix), ".crt")] = CertAndKey{cert: cert, key: signer}
+	}
+
+	return certs, nil
+}
+
+func (c *Config) reusable(existing CertAndKey, template *x509.Certificate, signer string, spec KeySpec) bool {
+	if existing.cert == nil || existing.key == nil {
+		return false
+	}
+	cert := existing.cert
+
+	// keep certificates which are still valid, but not those which outlive
+	// what would be issued now (e.g. because the validity was shortened)
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) || cert.NotAfter.After(template.NotAfter) {
+		return false
+	}
+
+	if cert.CheckSignatureFrom(c.cas[signer].cert) != nil {
+		return false
+	}
+
+	if !keyMatches(cert.PublicKey, existing.key) || keyAlgorithm(cert.PublicKey) != spec.Algorithm {
+		return false
+	}
+
+	// compare encoded subjects, as encoding sorts multi-valued attributes
+	subject, err := asn1.Marshal(template.Subject.ToRDNSequence())
+	if err != nil {
+		return false
+	}
+
+	if !bytes.Equal(cert.RawSubject, subject) ||
+		cert.KeyUsage != template.KeyUsage ||
+		!reflect.DeepEqual(cert.ExtKeyUsage, template.ExtKeyUsage) ||
+		!reflect.DeepEqual(cert.DNSNames, template.DNSNames) ||
+		len(cert.IPAddresses) != len(template.IPAddresses) {
+		return false
+	}
+	for i, ip := range cert.IPAddresses {
+		if !ip.Equal(template.IPAddresses[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,172 @@
+package certgen
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+	"gopkg.in/yaml.v2"
+)
+
+const manifestNamespace = "default"
+
+// The masters' client certificates must not be written as Secrets, as they
+// grant cluster-admin and etcd access.
+var manifestCerts = []string{"openshift-router", "registry"}
+
+type Secret struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   ObjectMeta        `yaml:"metadata"`
+	Type       string            `yaml:"type,omitempty"`
+	Data       map[string]string `yaml:"data,omitempty"`
+}
+
+type ConfigMap struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   ObjectMeta        `yaml:"metadata"`
+	Data       map[string]string `yaml:"data,omitempty"`
+}
+
+type List struct {
+	APIVersion string        `yaml:"apiVersion"`
+	Kind       string        `yaml:"kind"`
+	Items      []interface{} `yaml:"items"`
+}
+
+type ObjectMeta struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+func tlsSecret(name, namespace string, cert CertAndKey, encoding string, cas []*x509.Certificate) (*Secret, error) {
+	crt, err := certAsBytes(cert.cert)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := privateKeyAsBytes(cert.key, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	cacert, err := certAsBytes(cas...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Secret{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata: ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: "kubernetes.io/tls",
+		Data: map[string]string{
+			"ca.crt":  base64.StdEncoding.EncodeToString(cacert),
+			"tls.crt": base64.StdEncoding.EncodeToString(crt),
+			"tls.key": base64.StdEncoding.EncodeToString(key),
+		},
+	}, nil
+}
+
+func (c *Config) signer(cert *x509.Certificate) string {
+	for _, name := range CANames {
+		if ca, found := c.cas[name]; found && cert.CheckSignatureFrom(ca.cert) == nil {
+			return name
+		}
+	}
+	return ""
+}
+
+func (c *Config) WriteMasterManifests(fs filesystem.Filesystem, node *Node) error {
+	if !c.EmitManifests {
+		return nil
+	}
+
+	var items []interface{}
+
+	for _, filename := range manifestCerts {
+		cert, found := node.Master.certs[filename]
+		if !found {
+			return fmt.Errorf("%s: certificate not prepared", filename)
+		}
+
+		signer := c.signer(cert.cert)
+		if signer == "" {
+			return fmt.Errorf("%s: certificate not signed by any of the cluster's CAs", filename)
+		}
+
+		secret, err := tlsSecret(filename, manifestNamespace, cert, c.keySpec(masterKeyClass(filename)).Encoding, c.caBundle(signer))
+		if err != nil {
+			return err
+		}
+
+		err = writeManifest(fs, fmt.Sprintf("etc/origin/master/manifests/secret-%s.yaml", filename), secret)
+		if err != nil {
+			return err
+		}
+
+		items = append(items, secret)
+	}
+
+	cabundle, err := certAsBytes(c.caBundle("ca")...)
+	if err != nil {
+		return err
+	}
+
+	serviceca, err := certAsBytes(c.caBundle("service-signer")...)
+	if err != nil {
+		return err
+	}
+
+	configMap := &ConfigMap{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata: ObjectMeta{
+			Name:      "ca-bundle",
+			Namespace: manifestNamespace,
+		},
+		Data: map[string]string{
+			"ca-bundle.crt":  string(cabundle),
+			"service-ca.crt": string(serviceca),
+		},
+	}
+
+	err = writeManifest(fs, "etc/origin/master/manifests/configmap-ca-bundle.yaml", configMap)
+	if err != nil {
+		return err
+	}
+
+	items = append(items, configMap)
+
+	if !c.EmitManifestList {
+		return nil
+	}
+
+	secrets, err := c.componentSecrets()
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		items = append(items, secret)
+	}
+
+	return writeManifest(fs, "etc/origin/master/manifests/list.yaml", &List{
+		APIVersion: "v1",
+		Kind:       "List",
+		Items:      items,
+	})
+}
+
+func writeManifest(fs filesystem.Filesystem, filename string, m interface{}) error {
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return fs.WriteFile(filename, b, 0600)
+}
@@ -0,0 +1,85 @@
+package certgen
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+func TestWriteMasterManifests(t *testing.T) {
+	c := testConfig(t)
+	c.EmitManifests = true
+
+	fs := filesystem.NewMemoryFilesystem()
+	err := c.WriteMasterManifests(fs, &c.Nodes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := fs.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"etc/origin/master/manifests/configmap-ca-bundle.yaml",
+		"etc/origin/master/manifests/secret-openshift-router.yaml",
+		"etc/origin/master/manifests/secret-registry.yaml",
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("WriteMasterManifests wrote %s, want %s", strings.Join(files, ", "), strings.Join(want, ", "))
+	}
+}
+
+func TestWriteMasterManifestsShared(t *testing.T) {
+	c := testConfigFor(t, `
+apiVersion: certgen/v1
+kind: ClusterDescription
+externalMasterHostname: master.example.com
+externalRouterIP: 10.0.0.100
+manifests: {}
+nodes:
+- hostname: master1
+  ips: [10.0.0.1]
+  master: {port: 8443}
+- hostname: master2
+  ips: [10.0.0.2]
+  master: {port: 8443}
+keys:
+  ca: {algorithm: ecdsa-p256}
+  etcd-ca: {algorithm: ecdsa-p256}
+  master: {algorithm: ecdsa-p256}
+  etcd: {algorithm: ecdsa-p256}
+  node: {algorithm: ecdsa-p256}
+  router: {algorithm: ecdsa-p256}
+`)
+
+	var written [2]*filesystem.MemoryFilesystem
+	for i := range written {
+		written[i] = filesystem.NewMemoryFilesystem()
+		err := c.WriteMasterManifests(written[i], &c.Nodes[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, filename := range []string{
+		"etc/origin/master/manifests/secret-openshift-router.yaml",
+		"etc/origin/master/manifests/secret-registry.yaml",
+	} {
+		a, err := written[0].ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := written[1].ReadFile(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(a, b) {
+			t.Errorf("%s differs between masters", filename)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package certgen
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+// The named certificate is only served to clients connecting to the external
+// master hostname; other clients are still served master.server.crt.
+type namedCertificate struct {
+	CertAndKey
+	chain    []*x509.Certificate
+	cas      []*x509.Certificate
+	generate bool
+	existing map[string]CertAndKey
+}
+
+func (c *Config) SetNamedCertificate(certPEM, keyPEM, caPEM []byte) error {
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return fmt.Errorf("named certificate: %v", err)
+	}
+
+	privkey, err := ParsePrivateKey(keyPEM)
+	if err != nil {
+		return fmt.Errorf("named certificate: %v", err)
+	}
+	key, ok := privkey.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("named certificate: unsupported private key type %T", privkey)
+	}
+
+	if !keyMatches(certs[0].PublicKey, key) {
+		return fmt.Errorf("named certificate: private key does not match certificate")
+	}
+
+	cas, err := ParseCertificates(caPEM)
+	if err != nil {
+		return fmt.Errorf("named certificate CA: %v", err)
+	}
+
+	roots, intermediates := x509.NewCertPool(), x509.NewCertPool()
+	for _, ca := range cas {
+		roots.AddCert(ca)
+	}
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err = certs[0].Verify(x509.VerifyOptions{
+		DNSName:       c.ExternalMasterHostname,
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return fmt.Errorf("named certificate: %v", err)
+	}
+
+	c.named = &namedCertificate{
+		CertAndKey: CertAndKey{cert: certs[0], key: key},
+		chain:      certs[1:],
+		cas:        cas,
+	}
+
+	return nil
+}
+
+// public-ca can be distributed to browsers without them trusting the internal
+// CA.
+func (c *Config) GenerateNamedCertificate() {
+	c.named = &namedCertificate{generate: true}
+}
+
+func (c *Config) HasNamedCertificate() bool {
+	return c.named != nil
+}
+
+func (c *Config) PrepareNamedCertificate() error {
+	if c.named == nil || !c.named.generate {
+		return nil
+	}
+
+	now := time.Now()
+
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: c.ExternalMasterHostname},
+		NotBefore:             now,
+		NotAfter:              now.AddDate(2, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		DNSNames:              []string{c.ExternalMasterHostname},
+	}
+	c.applyProfile("named", template, c.cas["public-ca"].cert, now)
+
+	spec := c.keySpec(KeyClassRouter)
+
+	if existing, found := c.named.existing[c.ExternalMasterHostname]; found && c.reusable(existing, template, "public-ca", spec) {
+		c.named.CertAndKey = existing
+		return nil
+	}
+	serialNumber, err := c.serial.Get()
+	if err != nil {
+		return err
+	}
+	template.SerialNumber = serialNumber
+
+	certAndKey, err := newCertAndKey("named", template, c.cas["public-ca"].cert, c.cas["public-ca"].key, spec, false, false)
+	if err != nil {
+		return err
+	}
+
+	c.named.CertAndKey = certAndKey
+
+	return nil
+}
+
+func (c *Config) namedCAs() []*x509.Certificate {
+	switch {
+	case c.named == nil:
+		return nil
+	case c.named.generate:
+		return c.caBundle("public-ca")
+	}
+	return c.named.cas
+}
+
+func (c *Config) externalCABundle() []*x509.Certificate {
+	return append(c.caBundle("ca"), c.namedCAs()...)
+}
+
+func (c *Config) writeNamedCertificate(fs filesystem.Filesystem) error {
+	if c.named == nil {
+		return nil
+	}
+
+	err := writeCert(fs, fmt.Sprintf("etc/origin/master/named_certificates/%s.crt", c.ExternalMasterHostname), append([]*x509.Certificate{c.named.cert}, c.named.chain...)...)
+	if err != nil {
+		return err
+	}
+
+	err = writePrivateKey(fs, fmt.Sprintf("etc/origin/master/named_certificates/%s.key", c.ExternalMasterHostname), c.named.key, c.keySpec(KeyClassRouter).Encoding)
+	if err != nil {
+		return err
+	}
+
+	return writeCert(fs, "etc/origin/master/named_certificates/ca.crt", c.namedCAs()...)
+}
@@ -0,0 +1,141 @@
+package certgen
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Zero fields leave the default in place.
+type Profile struct {
+	Validity      time.Duration
+	KeyUsage      x509.KeyUsage
+	ExtKeyUsage   []x509.ExtKeyUsage
+	ExtraDNSNames []string
+	ExtraIPs      []net.IP
+	CommonName    string
+	Organizations []string
+}
+
+var masterCertNames = []string{
+	"admin", "aggregator-front-proxy", "etcd.server", "master.etcd-client",
+	"master.kubelet-client", "master.proxy-client", "master.server",
+	"openshift-aggregator", "openshift-master", "openshift-registry",
+	"openshift-router", "registry",
+}
+
+// Where several patterns match a name, an exact match is preferred, followed by
+// the longest pattern.
+func (c *Config) SetProfile(pattern string, profile Profile) error {
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return fmt.Errorf("profile %q: %v", pattern, err)
+	}
+
+	if c.profiles == nil {
+		c.profiles = map[string]Profile{}
+	}
+	c.profiles[pattern] = profile
+
+	return nil
+}
+
+func (c *Config) profile(name string) (Profile, bool) {
+	if profile, found := c.profiles[name]; found {
+		return profile, true
+	}
+
+	patterns := make([]string, 0, len(c.profiles))
+	for pattern := range c.profiles {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return c.profiles[pattern], true
+		}
+	}
+
+	return Profile{}, false
+}
+
+// A profile's validity cannot outlast issuer.
+func (c *Config) applyProfile(name string, template, issuer *x509.Certificate, now time.Time) {
+	profile, found := c.profile(name)
+	if !found {
+		return
+	}
+
+	if profile.Validity != 0 {
+		template.NotAfter = now.Add(profile.Validity)
+		if template.NotAfter.After(issuer.NotAfter) {
+			template.NotAfter = issuer.NotAfter
+		}
+	}
+	if profile.KeyUsage != 0 {
+		template.KeyUsage = profile.KeyUsage
+	}
+	if profile.ExtKeyUsage != nil {
+		template.ExtKeyUsage = profile.ExtKeyUsage
+	}
+	if len(profile.ExtraDNSNames) > 0 {
+		template.DNSNames = append(append([]string{}, template.DNSNames...), profile.ExtraDNSNames...)
+	}
+	if len(profile.ExtraIPs) > 0 {
+		template.IPAddresses = append(append([]net.IP{}, template.IPAddresses...), profile.ExtraIPs...)
+	}
+	if profile.CommonName != "" {
+		template.Subject.CommonName = profile.CommonName
+	}
+	if profile.Organizations != nil {
+		template.Subject.Organization = profile.Organizations
+	}
+}
+
+var KeyUsageNames = map[string]x509.KeyUsage{
+	"digitalSignature":  x509.KeyUsageDigitalSignature,
+	"contentCommitment": x509.KeyUsageContentCommitment,
+	"keyEncipherment":   x509.KeyUsageKeyEncipherment,
+	"dataEncipherment":  x509.KeyUsageDataEncipherment,
+	"keyAgreement":      x509.KeyUsageKeyAgreement,
+	"certSign":          x509.KeyUsageCertSign,
+	"crlSign":           x509.KeyUsageCRLSign,
+	"encipherOnly":      x509.KeyUsageEncipherOnly,
+	"decipherOnly":      x509.KeyUsageDecipherOnly,
+}
+
+var ExtKeyUsageNames = map[string]x509.ExtKeyUsage{
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+	"ocspSigning":     x509.ExtKeyUsageOCSPSigning,
+}
+
+func ParseValidity(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		var days int
+		_, err := fmt.Sscanf(s, "%dd", &days)
+		if err != nil || fmt.Sprintf("%dd", days) != s {
+			return 0, fmt.Errorf("invalid validity %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid validity %q", s)
+	}
+	return d, nil
+}
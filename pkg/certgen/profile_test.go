@@ -0,0 +1,17 @@
+package certgen
+
+import "testing"
+
+func TestProfileValidityClampedToIssuer(t *testing.T) {
+	c := testConfigFor(t, testDescription+`
+profiles:
+  system:node:*:
+    validity: 87600h
+`)
+
+	cert := c.Nodes[1].certs["system:node:node1"].cert
+	ca := c.cas["ca"].cert
+	if !cert.NotAfter.Equal(ca.NotAfter) {
+		t.Errorf("NotAfter = %s, want the CA's %s", cert.NotAfter, ca.NotAfter)
+	}
+}
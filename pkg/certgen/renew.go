@@ -0,0 +1,104 @@
+package certgen
+
+import (
+	"crypto/x509"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RenewCerts reissues the leaf certificates loaded for node which expire before
+// deadline, with the same subject, usages, SANs, signer and validity period (no
+// longer than the CA's), returning their names.  Keys are kept if keepKeys is
+// set.
+func (c *Config) RenewCerts(node *Node, deadline time.Time, keepKeys bool) ([]string, error) {
+	var renewed []string
+
+	type dir struct {
+		format string
+		certs  map[string]CertAndKey
+	}
+
+	dirs := []dir{{"etc/origin/node/%s.crt", node.existing}}
+	if node.Master != nil {
+		dirs = append(dirs,
+			dir{"etc/origin/master/%s.crt", node.Master.existing},
+			dir{"etc/origin/master/%s.crt", c.existingClusterCerts},
+			dir{"etc/origin/master/secrets/%s.yaml", c.existingComponentCerts},
+		)
+		if c.named != nil && c.named.generate {
+			dirs = append(dirs, dir{"etc/origin/master/named_certificates/%s.crt", c.named.existing})
+		}
+	}
+	if node.Etcd != nil {
+		dirs = append(dirs, dir{"etc/etcd/%s.crt", node.Etcd.existing})
+	}
+
+	for _, dir := range dirs {
+		for filename, existing := range dir.certs {
+			if !existing.cert.NotAfter.Before(deadline) {
+				continue
+			}
+
+			certAndKey, err := c.renewCert(existing, keepKeys)
+			if err != nil {
+				return nil, fmt.Errorf(dir.format+": %v", filename, err)
+			}
+
+			dir.certs[filename] = certAndKey
+			renewed = append(renewed, fmt.Sprintf(dir.format, filename))
+		}
+	}
+
+	sort.Strings(renewed)
+
+	return renewed, nil
+}
+
+func (c *Config) renewCert(existing CertAndKey, keepKey bool) (CertAndKey, error) {
+	signer := c.signer(existing.cert)
+	if signer == "" {
+		return CertAndKey{}, fmt.Errorf("certificate is not signed by any of the cluster's CAs")
+	}
+	ca := c.cas[signer]
+
+	now := time.Now()
+
+	serialNumber, err := c.serial.Get()
+	if err != nil {
+		return CertAndKey{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		Subject:               existing.cert.Subject,
+		NotBefore:             now,
+		NotAfter:              now.Add(existing.cert.NotAfter.Sub(existing.cert.NotBefore)),
+		KeyUsage:              existing.cert.KeyUsage,
+		ExtKeyUsage:           existing.cert.ExtKeyUsage,
+		BasicConstraintsValid: true,
+		DNSNames:              existing.cert.DNSNames,
+		IPAddresses:           existing.cert.IPAddresses,
+	}
+	if template.NotAfter.After(ca.cert.NotAfter) {
+		template.NotAfter = ca.cert.NotAfter
+	}
+
+	key := existing.key
+	if !keepKey {
+		algorithm := keyAlgorithm(existing.key.Public())
+		if algorithm == "" {
+			return CertAndKey{}, fmt.Errorf("unsupported private key type %T", existing.key)
+		}
+
+		var err error
+		key, err = generateKey(algorithm)
+		if err != nil {
+			return CertAndKey{}, err
+		}
+	}
+
+	// certificates signed by the etcd CA carry the etcd-specific authority key
+	// identifier
+	return newCert(template, ca.cert, ca.key, key, false, signer == "master.etcd-ca")
+}
@@ -0,0 +1,181 @@
+package certgen
+
+import (
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/jim-minter/certgen/pkg/filesystem"
+)
+
+// A CA rotation takes three phases, each rolled out to every host before the
+// next: BeginCARotation creates a new CA which is trusted alongside the old;
+// PromoteCAs makes it the signing CA; FinishCARotation drops the old CA.
+// The CAs must already be set, typically by Load.  CAs supplied with SetCA
+// cannot be rotated, as the description would still supply the old CA to later
+// phases.
+func (c *Config) BeginCARotation(names ...string) error {
+	if len(c.nextCAs) > 0 || len(c.previousCAs) > 0 {
+		return fmt.Errorf("a CA rotation is already in progress")
+	}
+
+	now := time.Now()
+
+	templates := c.caTemplates(now)
+	if len(names) == 0 {
+		for _, cacert := range templates {
+			if !c.suppliedCAs[cacert.filename] {
+				names = append(names, cacert.filename)
+			}
+		}
+	}
+
+	for _, cacert := range templates {
+		var selected bool
+		for _, name := range names {
+			selected = selected || name == cacert.filename
+		}
+		if !selected {
+			continue
+		}
+
+		if _, exists := c.cas[cacert.filename]; !exists {
+			return fmt.Errorf("CA %q: no existing CA to rotate", cacert.filename)
+		}
+		if c.suppliedCAs[cacert.filename] {
+			return fmt.Errorf("CA %q: supplied by the cluster description, so it must be replaced there rather than rotated", cacert.filename)
+		}
+
+		certAndKey, err := c.newCA(cacert.filename, cacert.template, now)
+		if err != nil {
+			return err
+		}
+
+		if c.nextCAs == nil {
+			c.nextCAs = map[string]CertAndKey{}
+		}
+		c.nextCAs[cacert.filename] = certAndKey
+	}
+
+	for _, name := range names {
+		if _, found := c.nextCAs[name]; !found {
+			return fmt.Errorf("unknown CA %q", name)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) PromoteCAs() error {
+	if len(c.nextCAs) == 0 {
+		return fmt.Errorf("no new CA is awaiting promotion")
+	}
+
+	c.previousCAs = map[string]*x509.Certificate{}
+	for name, ca := range c.nextCAs {
+		c.previousCAs[name] = c.cas[name].cert
+		c.cas[name] = ca
+	}
+	c.nextCAs = nil
+
+	return nil
+}
+
+func (c *Config) FinishCARotation() error {
+	if len(c.nextCAs) > 0 {
+		return fmt.Errorf("the new CAs have not been promoted")
+	}
+	if len(c.previousCAs) == 0 {
+		return fmt.Errorf("no CA rotation is in progress")
+	}
+
+	c.previousCAs = nil
+
+	return nil
+}
+
+func (c *Config) rotatingCAs(name string) []*x509.Certificate {
+	var certs []*x509.Certificate
+	if ca, found := c.nextCAs[name]; found {
+		certs = append(certs, ca.cert)
+	}
+	if cert, found := c.previousCAs[name]; found {
+		certs = append(certs, cert)
+	}
+	return certs
+}
+
+func (c *Config) caBundle(name string) []*x509.Certificate {
+	return append(c.caChain(name), c.rotatingCAs(name)...)
+}
+
+func (c *Config) writeRotatingCAs(fs filesystem.Filesystem) error {
+	for filename, ca := range c.nextCAs {
+		err := writeCert(fs, fmt.Sprintf("etc/origin/master/%s.next.crt", filename), ca.cert)
+		if err != nil {
+			return err
+		}
+
+		err = writePrivateKey(fs, fmt.Sprintf("etc/origin/master/%s.next.key", filename), ca.key, c.keySpec(caKeyClass(filename)).Encoding)
+		if err != nil {
+			return err
+		}
+	}
+
+	for filename, cert := range c.previousCAs {
+		err := writeCert(fs, fmt.Sprintf("etc/origin/master/%s.previous.crt", filename), cert)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) loadRotatingCAs(r filesystem.Reader, exists map[string]bool) error {
+	for _, name := range CANames {
+		certfile, keyfile := fmt.Sprintf("etc/origin/master/%s.next.crt", name), fmt.Sprintf("etc/origin/master/%s.next.key", name)
+		if _, set := c.nextCAs[name]; !set && exists[certfile] && exists[keyfile] {
+			certPEM, err := r.ReadFile(certfile)
+			if err != nil {
+				return err
+			}
+
+			keyPEM, err := r.ReadFile(keyfile)
+			if err != nil {
+				return err
+			}
+
+			ca, err := parseCA(name, certPEM, keyPEM)
+			if err != nil {
+				return err
+			}
+
+			if c.nextCAs == nil {
+				c.nextCAs = map[string]CertAndKey{}
+			}
+			c.nextCAs[name] = ca
+			c.serial.Observe(ca.cert.SerialNumber)
+		}
+
+		certfile = fmt.Sprintf("etc/origin/master/%s.previous.crt", name)
+		if _, set := c.previousCAs[name]; !set && exists[certfile] {
+			b, err := r.ReadFile(certfile)
+			if err != nil {
+				return err
+			}
+
+			certs, err := ParseCertificates(b)
+			if err != nil {
+				return fmt.Errorf("%s: %v", certfile, err)
+			}
+
+			if c.previousCAs == nil {
+				c.previousCAs = map[string]*x509.Certificate{}
+			}
+			c.previousCAs[name] = certs[0]
+		}
+	}
+
+	return nil
+}
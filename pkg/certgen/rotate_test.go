@@ -0,0 +1,38 @@
+package certgen
+
+import (
+	"encoding/pem"
+	"strings"
+	"testing"
+)
+
+func TestBeginCARotationSuppliedCA(t *testing.T) {
+	c := testConfig(t)
+
+	ca := c.cas["frontproxy-ca"]
+	key, err := privateKeyBlock(ca.key, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.SetCA("frontproxy-ca", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}), pem.EncodeToMemory(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.BeginCARotation("frontproxy-ca")
+	if err == nil || !strings.Contains(err.Error(), "supplied by the cluster description") {
+		t.Errorf("rotating a supplied CA: got error %v", err)
+	}
+
+	err = c.BeginCARotation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found := c.nextCAs["frontproxy-ca"]; found {
+		t.Error("rotating every CA rotated the supplied CA")
+	}
+	if _, found := c.nextCAs["ca"]; !found {
+		t.Error("rotating every CA did not rotate ca")
+	}
+}
@@ -1,6 +1,6 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
-// master/etc/etcd/etcd.conf
+// etcd/etc/etcd/etcd.conf
 // master/etc/origin/master/htpasswd
 // master/etc/origin/master/master-config.yaml
 // master/etc/origin/master/openshift-ansible-catalog-console.js
@@ -75,22 +75,22 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _masterEtcEtcdEtcdConf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xac\x54\x4d\x6f\xe2\x30\x10\xbd\xe7\x57\x20\x73\xd9\x3d\xb4\xa4\xf4\x6b\xa9\xe4\x83\x71\x86\x62\x61\x12\xd6\x9e\x40\x51\x55\x59\x94\x06\x1a\x2d\x05\x94\x84\x6e\xab\x8a\xff\xbe\xca\x07\x84\x16\xf6\x43\xda\x9e\x20\xf3\x9e\xc7\x6f\x66\xde\x18\x90\x3b\xc6\x65\x5d\xa0\x6f\x6f\x95\x2f\xe1\xfc\x21\x78\xa9\x1c\xbb\x8b\x87\x20\xae\xd8\x5f\x8f\xdb\x8b\x38\x99\x8f\x9e\x82\xca\x7a\x6d\x65\x4c\x29\x34\x82\x6b\x7a\x00\xca\xf8\x4a\x6a\xfa\x98\x24\xcb\xf8\xaa\x56\x2b\x4f\xef\x25\x11\xbd\xec\x47\x27\x51\x38\x9f\x56\xd6\xeb\xab\xfa\xe9\x37\x3b\x4f\xe7\x30\x64\xc6\x11\x8a\xd6\x9e\x47\x51\x6d\x16\xde\xd7\x82\x64\xfc\x50\xb3\xaa\x19\x3a\x60\x32\x03\x09\x29\x02\xda\x65\x3d\xdd\xf6\xd0\x70\xcf\x77\x91\x9e\xd8\xb6\x5d\x24\x6a\x03\x53\xd8\x04\x86\x46\xb8\x08\xaa\xcf\x24\x3d\xdf\x60\x20\x81\xa3\xf0\x5c\x83\xa2\x0b\x9e\x8f\xb4\xbe\x85\x8a\x72\xb8\x14\xe0\xe2\xff\x14\x74\xd9\x28\x24\x76\xd9\xcd\x56\xa6\xa6\xe7\x3b\xd1\x01\x93\x65\x80\x7b\x4a\x53\xcb\xb2\xaa\xb7\xe3\xd9\x2a\x4e\x82\xe8\x2e\x17\x24\x5c\x81\x82\x49\xc3\x9c\x3e\x28\x14\x1a\x3e\xad\xd5\x9b\xcc\x5c\xfa\x1a\x41\xfd\x6d\xdc\x9f\x7b\x9b\xd1\xc8\x10\xe8\x3c\xf8\x79\x18\x46\xaf\x03\x2e\x4d\x67\x7f\x54\xf4\xe3\xe8\xa4\xe8\x94\x23\x34\xf7\xfa\xa0\x86\xf4\x63\xc0\x68\xd5\xdf\x0f\xb6\x98\x94\x4d\xc6\x3b\x74\x19\x2d\x5e\x5e\xf7\xe0\x9e\xf2\x6e\x86\x34\x57\x51\x36\xf9\x13\x0d\xa0\x51\x09\x8e\x46\x01\xf7\xdc\x96\xb8\x36\xbc\x0d\xbc\x43\xc9\x64\x34\x8b\x83\x8d\x8f\x99\x8f\x9e\xe1\x5e\xb7\xc7\x72\x63\x2a\x40\x70\xd3\x7f\x94\xd8\x1b\x0e\xb8\xac\x29\xc1\xf4\xeb\x94\x24\xd1\x2a\x20\xb9\xe4\xef\xbe\x87\xcc\xa4\xf5\x81\xeb\x98\xe6\x10\x41\xd3\xb3\x7a\xe3\xac\x71\x71\x59\x6f\x5c\xa4\x7e\xca\xca\xbe\x2b\x92\xe4\xd5\x2e\x26\x93\xdd\x6f\xd3\x62\x42\xfa\x0a\xcc\x80\x09\xa4\xe4\xdc\xb6\xb7\x97\xe6\xb8\x82\x96\x02\xdd\x2e\x57\x89\x9c\xda\x7b\x24\x27\x9d\xdf\x66\xa3\xc8\xc9\x1e\x3e\x50\x02\xa1\x24\x1c\xba\x85\x39\x25\x6e\x93\x54\x7c\x1c\x8c\x57\x51\x98\xbc\x16\xdb\x80\x2a\xb5\x87\x63\x38\x33\x2d\x21\x81\xa6\xaf\x43\xfe\x42\x8c\x47\xc7\xe3\x28\xc9\x59\xc5\xf0\x38\x28\x4c\x3b\xdb\x7e\xd7\xb0\x2c\xfa\xe1\x70\x1c\x44\xcf\x41\x54\x26\xe8\xc0\xf0\x37\x94\x1f\xc1\xeb\xee\xc8\x50\xea\xed\x24\xf3\x4a\xd2\xe5\xfc\x37\x95\x19\xf5\x8f\x52\x73\xc6\x01\xbd\xcb\x60\x57\x6d\x46\x3b\x20\x39\x63\x95\x82\x33\xda\x9e\x6a\xab\x7a\x3b\x5b\x4c\xa7\xe1\x7c\x5a\xb4\xd8\x81\xa6\x7f\x4d\x49\x6b\x0b\x2f\xa3\xc5\x24\x9c\x65\x84\x77\x46\xec\xf5\x94\xd7\xfa\xe0\xe3\x2e\xa4\x66\xd7\x94\xdc\x8f\xe2\x70\x4c\xac\xaa\x55\xbd\x1d\xad\x92\xc7\xbb\xb2\x69\xed\x62\xb7\x49\x1c\x3e\x2d\x67\x01\xb1\x7e\x05\x00\x00\xff\xff\xc6\x94\x96\xe4\x71\x06\x00\x00")
+var _etcdEtcEtcdEtcdConf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x54\x4d\x6f\x83\x38\x10\xbd\xf3\x2b\x10\xe9\xb1\x6d\x68\xfa\xb5\xa9\xe4\x83\x03\x93\xc6\x0a\x01\xd6\x36\x49\xa3\xaa\xb2\x68\xe2\xa4\x68\xf3\x25\x20\xdd\xad\x10\xff\x7d\x85\x81\x7c\x34\xe9\xaa\x97\x3d\x54\x6a\x66\xde\x8c\xdf\x9b\x79\x03\x70\xcb\x16\x2e\x1e\x00\xca\x32\xfd\xda\x5d\x4f\xe5\x75\x6f\x9d\xa4\xab\x70\x29\xf5\x3c\xd7\x54\xda\x21\x8c\x83\x2b\x7c\x00\x2a\x02\xea\xb0\x02\x1a\x87\xab\xb9\xd4\x2f\xa2\x4b\xfd\x22\xda\xe8\x4f\xa8\xaa\x25\x7e\xa2\xe7\x79\x96\xe9\xd1\x4c\xbf\x88\xf4\x3c\xbf\xcc\x32\x5d\xae\xa6\x7a\x9e\x7f\xa4\xe9\x26\x79\x6a\x36\xb3\x4c\x2f\x5e\xf0\xd7\x71\xaa\x6a\x5b\xb7\x7f\x98\x65\x4d\x89\x2b\xdf\xb4\x31\xc7\xc2\x26\x14\x35\x3f\xc3\xb8\xb9\x88\xde\x9b\x32\x9d\x4c\x9b\x5a\x43\x65\x47\xd8\x51\x49\xc3\xa8\x02\xcc\xc5\x3e\xeb\x79\x5c\x58\x5e\xe0\x72\x74\x63\x9a\xa6\x59\x36\xea\x01\xa6\xbc\x03\x98\x0b\xe2\x72\xa0\x43\xec\xa0\xfb\x3a\x07\x0e\x58\x9c\x78\xae\xe0\x64\x00\x5e\xc0\x51\x6b\x97\xaa\x34\x5b\x0e\x01\x97\xff\x2f\xaa\x1f\xdb\x47\xaa\x4b\x1d\x03\xfc\xb2\xd3\xc2\xd0\x7d\xa5\xae\x88\x8e\xb0\xb3\x0f\x58\x1e\x65\x48\xd3\xb4\xc6\xeb\x64\xb1\x4d\x52\x19\xbf\x95\x82\x88\x4b\x38\xc1\x8e\xc0\xf6\x10\x28\x27\x0c\x0e\x96\x76\x8e\x49\x49\xdf\x8f\xa3\x65\x18\x7f\x11\xbf\xde\xc5\x71\x33\xcb\x09\x18\x07\xfa\x4d\xff\x52\x2e\xdf\x65\xac\x66\x00\xe9\x64\x3a\x50\x3f\x7f\x1c\x43\x96\xd5\x15\x87\xf6\x42\x67\xa7\x53\xe1\x4e\x58\xed\x67\x75\x8e\x9e\x60\x1c\x73\x40\x2b\xf9\xf7\xf9\x34\xf7\xfa\xe0\xa2\xc2\x44\x57\xd5\xcc\xae\x6e\xaa\x69\xda\x84\x59\xde\x10\xe8\x18\x7d\x0f\x08\x46\x87\xa7\xc1\x2e\x76\x9c\x0e\xb6\xfa\x68\x13\xaf\xff\xf9\x3a\x49\xfb\xd4\x7b\x19\xa3\x92\xc5\x7e\x11\x87\x4e\xfa\xdd\x2a\x94\x41\xaa\xee\x8c\x53\x62\x71\x41\xc1\xf2\xdc\x2e\x79\x16\x56\x0f\xac\x3e\x32\x66\xe1\x22\x91\xf5\x0d\xe0\x80\x7b\xc2\xf2\x06\x3e\x2e\x4d\x4d\x81\x83\x5b\xfc\x87\x0c\xb3\xc6\x80\x8b\x3b\x0e\x88\x61\x0b\x19\x69\xbc\x95\x46\xc9\xf2\xcf\xc0\xe3\x58\x14\x92\xc0\xb5\x45\x67\xcc\x81\xa1\xbb\x56\xfb\xae\xfd\xf0\xd8\x6a\x3f\x14\x36\x53\x4a\xdf\xaa\x26\xa5\xc0\xf5\x6c\x76\xf8\x5b\x74\x31\x71\x02\x0a\x62\x84\x09\x47\xc6\xbd\x69\xee\x1e\x55\x78\x41\xa1\x4b\x81\xf5\xf6\x67\x68\xdc\x9a\x27\x20\xbb\x58\x59\x7d\x8d\xc6\xcd\x49\x7e\x44\x09\x87\x3d\xe0\xdc\x2b\xd8\xde\xe7\x4d\xa3\x20\x9f\xc8\xc9\x36\x8e\xd2\xaf\xea\x48\x38\x2d\x1c\x61\x0b\x0b\x8b\x2e\x71\x00\x15\x5f\x96\xe2\x6f\xda\x9c\x84\xd7\x93\x38\x2d\x51\xd5\xbe\x2c\xa0\x5c\xe0\x80\xf7\x8e\x06\xa6\xa2\xdf\x8a\x13\x19\x7f\xca\x78\xdf\xa0\x0f\xe3\x1f\x20\x7f\xc9\xda\x34\x6a\x65\xdc\x61\xbb\x4d\xaa\xee\xea\x66\x7f\xc7\x52\x41\xff\x93\x6a\x89\x38\xc3\x77\x23\x0f\xd9\x2a\xd8\x19\xca\x1b\x79\x44\x58\xc1\x4e\x58\x6b\x8d\xd7\xc5\x7a\x3e\x8f\x56\xf3\x6a\xc4\x36\x74\x82\x67\x64\x74\x77\xe9\x4d\xbc\x9e\x45\x0b\x05\x38\x32\xa2\xef\x53\xaf\xbb\xeb\x53\x7d\xed\xa0\x30\x3b\x43\xc6\x7b\x98\x44\x13\x43\x6b\x68\x8d\xd7\x70\x9b\x7e\xd4\xa5\x85\xc4\xea\x9c\x8d\x24\x5a\x6e\x16\xd2\xd0\xfe\x1d\x00\x92\xec\xc9\x4a\xc7\x06\x00\x00")
 
-func masterEtcEtcdEtcdConfBytes() ([]byte, error) {
+func etcdEtcEtcdEtcdConfBytes() ([]byte, error) {
 	return bindataRead(
-		_masterEtcEtcdEtcdConf,
-		"master/etc/etcd/etcd.conf",
+		_etcdEtcEtcdEtcdConf,
+		"etcd/etc/etcd/etcd.conf",
 	)
 }
 
-func masterEtcEtcdEtcdConf() (*asset, error) {
-	bytes, err := masterEtcEtcdEtcdConfBytes()
+func etcdEtcEtcdEtcdConf() (*asset, error) {
+	bytes, err := etcdEtcEtcdEtcdConfBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "master/etc/etcd/etcd.conf", size: 1649, mode: os.FileMode(436), modTime: time.Unix(1521079494, 0)}
+	info := bindataFileInfo{name: "etcd/etc/etcd/etcd.conf", size: 1735, mode: os.FileMode(436), modTime: time.Unix(1792314846, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -115,7 +115,7 @@ func masterEtcOriginMasterHtpasswd() (*asset, error) {
 	return a, nil
 }
 
-var _masterEtcOriginMasterMasterConfigYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x58\x7b\x6f\x1b\xb9\x11\xff\x7f\x3f\x05\x71\x77\x40\x92\xa2\xbb\x2b\xc9\xc9\x25\x59\xe0\x50\xb8\xbe\xa4\x31\xce\x4e\x54\xcb\x57\x14\xa8\x8b\x82\x22\x47\x2b\x46\x5c\x72\x8f\x0f\xc5\x8a\xeb\xef\x5e\xf0\xa1\x5d\x6a\x25\x39\x46\x7a\xc1\x39\x81\x2d\x91\xbf\x19\x0e\x67\x86\xf3\xc2\xb4\x61\x5a\x33\x29\xce\xa4\x58\xb0\xba\xca\x10\x6a\xb9\xad\x59\xf2\x1d\xa1\xbf\x5a\xc6\xe9\xcf\xb0\xc0\x96\x1b\x1d\x96\x10\x22\x1e\x60\x15\x36\x4c\x8a\xed\x22\x42\xb8\x65\xff\x00\xe5\x38\x56\x68\x3d\xee\x96\x41\xac\x2b\xf4\xaf\x7f\x77\xdf\x57\x4c\xd0\x6a\x97\x71\x38\xb1\x43\x28\xd0\xd2\x2a\x02\xba\xe7\x8d\x10\x67\x0d\x33\xba\x42\x77\xf7\xc9\xa2\x82\xdf\x2c\xe8\x64\xd9\xb3\xfd\xb0\x06\xa5\x18\x85\xaf\x14\x38\x11\xb0\xe3\x94\x48\x38\x95\x74\xaa\x40\x83\xf9\x3a\xee\x94\x69\x3c\xe7\x50\xa1\x05\xe6\x1a\x06\x87\x46\x85\x9c\xee\x9a\xc6\x83\x64\x0b\x42\x2f\xd9\xc2\x14\x4c\x96\xe7\x0d\xae\x61\x2a\x39\x23\x9b\xaf\x34\xca\x2d\x10\xeb\x90\x57\x96\xa7\x7a\xce\x51\x83\x0d\x59\x7a\xfe\xa7\x42\x48\xe3\xd9\xed\x18\x22\x47\x2b\xd8\x54\x88\x39\x88\x2e\x76\xc4\xa2\x20\x36\x79\xc7\x3a\xa1\x41\x68\x8d\xb9\x85\x0a\x3d\x31\xca\xc2\x93\x64\x47\xe0\x06\xaa\x5e\x9c\x9c\x82\x60\x40\x13\x80\x14\x57\x87\xdc\x21\xef\xbc\xa4\x42\xad\xa4\xfa\xc8\xd6\xdc\x59\x51\xef\x78\xcc\x47\x20\xa6\x42\x4e\x8e\x64\x59\xaf\x58\xfb\xc1\x9f\xc4\xbd\x1c\x6f\x31\xe3\x56\xc1\x00\x17\x8c\x94\x28\x3f\xda\x07\xd7\xb5\x82\x1a\x1b\xa9\x92\xb7\xa4\xe4\xed\xe6\x8c\x33\x10\xe6\x5c\x2c\x64\x90\x9d\x80\x32\x6f\x99\xb3\x7e\x4f\x92\x2f\x94\x14\x26\xf7\xf8\x82\x28\xe3\x81\x2b\xd8\x3c\x88\x5b\xc1\x26\xc3\x2d\xbb\x80\x35\x70\x5d\x65\xb9\xb3\xed\xc0\xd4\x58\x6b\x30\xbd\x3c\x70\x6b\x40\xb8\xcd\x19\x51\xac\x0d\x8f\x39\x47\x25\x18\x52\x4a\xc5\x6a\x26\xca\x06\x6b\x03\xaa\xec\x2c\x9a\x63\xa1\xd9\x9c\x43\x4e\xb0\xc1\x5c\xd6\x39\x91\x42\x4b\x0e\xc5\x47\xa7\x50\x2e\x6b\x69\xcd\xaf\x57\x17\x15\xfa\xee\xbb\x0c\xa1\x40\x3d\xb5\x73\xce\x88\x5f\x5d\x1a\xd3\xea\xaa\x2c\xef\xee\x50\xf1\xe6\xd6\x80\x12\x98\x5f\x7a\xd0\x3b\xa9\x8d\xb3\x3b\xba\xbf\xaf\xee\xee\xd0\x53\x26\x28\xdc\xa2\xe2\xbd\xa4\xa0\xd1\xe8\x59\x11\x50\xc5\x54\x2a\x83\xee\xdd\xbb\x6e\x7f\x7f\xae\x65\xbc\x4c\x99\x21\xa4\x41\xad\x99\xa8\x7b\x33\xcd\x99\xa0\xa7\x94\x2a\xd0\xba\x42\xa3\xc2\xff\x7b\x9c\xa4\x81\xf6\x3d\x98\x4f\x52\xad\x2a\x64\x48\xfb\x7c\x60\xf8\xa0\xa7\xc2\x9d\x09\xaa\xb3\x37\xf1\x8e\x72\x76\x1a\x95\x99\x38\xc0\x2e\xde\xd9\xdd\x6d\x37\xf8\xf6\x2a\xc6\xbe\x73\xf1\x96\xb3\x7a\x69\x2a\x34\xca\x92\x98\x78\xcd\x1a\x90\xd6\xcc\x80\x48\x41\xdd\x35\x32\x6c\xcd\xb2\xf7\x87\x08\x7b\x07\x98\x82\xaa\x06\x42\x24\xae\x96\x13\x3c\x94\x52\x36\x8d\x14\xef\x71\xb3\x7d\x91\xf9\x11\x2f\xcd\x42\xa4\x31\x0a\x87\x53\xa6\x0a\x16\xec\xb6\xa7\xfa\x67\x7e\x05\x8d\x34\x90\xbf\x71\x98\xdc\xaf\xd6\x4a\xda\x36\xc0\xf7\x71\x7f\x73\x9b\x7e\xd1\x6a\x67\xf9\x06\x8e\x21\x7f\xd5\xa0\x32\x22\x85\x51\x92\x73\x48\x9e\x25\x70\x20\x7d\x84\xe4\x92\xac\xde\xfb\x08\xd4\x7b\x7d\x50\x78\xde\x13\xeb\xad\x8b\x10\x98\x05\x4f\x39\x03\x15\xa3\xbf\x66\xb5\xd8\xaa\x2f\xb5\x72\xc4\xe7\x61\xbf\x53\x60\x62\xd7\x01\xc2\x19\x36\x39\xb2\x42\x4f\xfe\xf4\x24\x23\x52\xe9\x53\xce\xe5\x27\xa0\x1f\xfc\x1b\xf5\x4f\xfd\xe9\x5f\xd8\xb3\xb2\x1c\x4f\x5e\xde\x14\x23\xff\x7f\xfc\xb4\xfa\xef\xcd\xe7\x67\xdd\x16\x97\x04\xf3\xa5\xd4\x66\xb0\x7e\x77\x87\xfe\x6e\xa5\x81\x4b\x30\x78\xeb\xcb\x7b\x2e\x7d\x3e\xf5\x7f\x66\x46\x31\x51\xa3\xfb\xfb\x47\xf0\xe8\x89\x93\x37\x38\xa0\x5b\xd9\x39\x28\x01\x06\xf4\x4d\x41\x43\x96\xfb\x32\xe2\xa6\xd0\x6b\x72\x53\x10\x6e\x9d\x49\x6e\x0a\x7f\xb1\xa3\x64\x83\x8d\xce\xa2\xc7\x0e\xdc\x07\xf8\xf3\x1e\x29\xd6\x00\x36\x7e\x39\xb9\x29\x4e\x0e\xdb\xe3\xc8\x41\x5f\xb8\xd8\x8e\xa6\x8f\xc7\xba\x63\x67\xc5\x75\x2a\x74\xef\xfc\x07\xe3\xda\xab\xd1\x8b\x93\xec\x50\xdc\x02\x43\xe8\x6e\x02\x23\xb8\x8b\x47\x6e\xb3\x0f\x0d\x7b\xe1\x2d\x6c\x7b\xe2\x08\x19\x06\xb4\x14\x11\xa2\x9a\x55\x3c\x66\xa5\x24\xc8\x3f\xe4\x62\xd5\xe4\xe4\xe5\x6b\x2f\xe6\xcc\x48\x85\x6b\xe8\x6f\xda\x5b\x2d\x6e\x85\xd8\x53\x25\x1b\x05\x93\x87\x80\xbb\xd5\x92\x53\xe7\xcc\xa9\x73\xc0\x26\x2d\x7b\x0e\xc0\x52\x26\xbe\x50\xea\x25\x5b\x48\xd5\x60\x93\x70\x38\x29\xa5\x86\xfc\x87\x3b\x22\x9b\x56\x0a\x10\xe6\xbe\xfa\xe1\x6e\x1d\x18\xb8\x94\xc2\xb1\x01\x6d\xb6\xf5\x62\x28\x41\x82\x17\xc4\xea\xc3\x5d\x81\x83\xd9\x37\x15\xc1\xf9\xdc\x0a\xca\xe1\x98\x95\x22\xe5\xc3\x86\x1a\x80\x82\xad\x5a\xa9\x4c\x85\xc6\xa3\xc9\x8b\x51\xd6\xab\x30\x15\xcb\x09\x81\x5b\x36\xf3\x79\xeb\x54\xd5\xb6\x01\xe1\xaa\x8e\xef\x43\x22\x91\x96\xe6\x24\x02\xbf\x0f\xc1\xdb\xd7\x22\xf8\xb3\x55\x10\x7e\x17\x6e\x3f\xc5\xb7\x4a\xae\x99\xcf\x56\x91\xc2\xc3\x42\xd2\xb3\xc2\xb0\x06\x3a\x96\x71\xbf\x65\xba\xd4\x60\x0c\x13\xb5\x2e\x56\xaf\x9c\xcd\xcb\xf5\x18\xf3\x76\x89\xc7\x3f\x75\xa5\x9d\x0e\x46\xcb\xe7\x98\xac\x40\xd0\x2d\xb5\x73\xac\x93\x1d\x40\x03\x94\xe1\xdc\x6c\x5a\xe8\x4f\x68\x39\x23\xbe\x48\x2e\xd7\x82\x16\x89\x7b\xb5\x4a\x1a\x39\xb7\x8b\xcc\xd7\xe6\x31\xb0\x7f\x63\x45\x34\xd1\x00\x56\x38\xeb\x74\x0b\xe7\xd3\x0a\xf5\x6f\xe9\x8b\x21\xdf\xdb\x97\xbe\x59\x33\x9f\x29\x63\x21\xf1\x88\x9a\x36\x3a\x4c\x2c\x1b\x52\x9f\xda\xf7\xaa\x1d\x50\xf0\x29\x4d\x96\x40\xed\xae\x92\x92\xd5\xe0\x56\x81\xc9\x81\xb2\xb5\xc3\x15\x1f\xb5\xef\x3d\x62\x82\xd5\xee\x9a\xae\x40\xbb\xc2\xa2\x86\x58\x5f\x6d\xf7\x66\x76\x2e\xc0\xa9\xea\xe5\xa4\x38\xf1\xe1\xb0\x1c\xff\xe8\xf6\x5d\xdf\x43\x1c\x65\x28\x73\x5c\xff\x1a\x75\xeb\x25\xd6\xdb\x8a\xda\x85\xe4\x5f\x3a\x9b\x9f\xc5\xfa\x48\x88\x50\x64\x0c\x1a\x51\x4c\x08\xb4\x6e\xdb\x80\x30\xd7\x9b\xd6\x31\x7e\x84\x03\xfd\x39\xc5\xc4\xcb\x21\x34\xb7\xca\x45\x85\xe7\xa3\x50\xf5\x91\x9e\xeb\xa3\x98\x7a\xa2\xdf\x5a\x5d\xa1\x89\xe7\xb0\x7f\x19\xf7\x29\x3e\xe5\xa0\xb4\x2e\x62\x5d\x48\xd9\xba\xb7\xf2\x07\x5c\xf7\xc7\xff\xfb\xba\x27\x9e\xc3\xde\x5d\xd2\xdb\x0e\x6b\x41\xcf\x30\xbc\xd1\xec\xdb\xb5\x39\x22\x24\xdf\x3e\x7a\xc6\xea\x20\x26\xe5\xb3\xf3\x9f\xaf\x5c\xc4\x2d\xc6\x93\x57\xc1\x51\x9f\xef\x61\x62\xfa\x24\x8c\xaa\x7d\x28\x42\xae\x24\x0c\x1e\x7f\x01\xa2\x36\xcb\x0a\xbd\x4e\x2c\x7f\x3e\x4d\x4e\x8a\x9c\x62\x8d\x50\x3a\x95\x1d\xa6\x8e\x52\x4f\xfd\xe4\x28\x94\xd2\x0a\xe8\x12\x9b\xa4\x8f\x94\x6b\x9d\x37\x96\x1b\x66\x40\x60\x61\xfa\xf7\xb7\x7b\xb5\x9d\x37\x28\x77\xbb\x15\xdf\xcc\x7e\x03\xad\xa7\x6d\x60\xad\xb0\x30\xe9\xf0\xab\x01\xb3\x94\xb4\x42\xd8\x1a\x97\xe4\x19\x05\x61\x98\xd9\x4c\x63\xf4\xdd\x6a\x7b\x89\x39\x07\x1f\x5b\xba\x8c\xc2\x65\xcd\x44\xf2\xbd\xc1\x6d\xcb\x44\x7d\x19\x19\x12\x8e\x59\x93\xf5\xe3\x8f\xa5\x69\xb1\xd6\x9f\xe8\x7f\xdc\xa5\xfd\x7a\x1f\xe1\x43\xdb\x70\x70\x8c\xb3\x38\x16\x0b\xb7\xfc\xb2\x74\x6c\xf1\xee\x7a\xea\x17\xfd\x6f\xa9\xe8\xf9\xe0\x3a\x7d\xfa\x38\xdd\x2f\x1d\xbe\x65\x77\x1f\x78\xff\xde\x5c\x35\x0c\x86\x9b\xdd\xd2\x25\xbe\x3d\xad\xa1\x6b\x8c\x4f\xb6\x31\x25\x6e\x07\x27\xd6\x5a\xa4\x8b\x33\x20\x0a\x8c\x3e\x9e\x7d\x02\x2c\xd7\x01\x57\x6c\x70\xc3\x33\x84\x8c\x5c\xc1\x8e\x08\x2e\x26\x6a\x7d\xed\x96\x07\x62\x4c\x9e\x8f\x5f\x4f\xa2\x24\xce\x0d\xa4\x62\x9f\xe1\x10\xf0\xc5\x68\x94\xb5\xd8\x6a\x17\xae\xfa\x76\x31\x54\x87\x6d\x32\x95\xf2\x05\xbf\x94\x46\x1b\x85\xdb\x30\xae\x3a\x2a\x7e\xa0\xdb\x66\xce\xee\xe5\x9e\x8b\x85\xc2\xda\x28\x4b\x8c\x55\x21\x15\xb6\x98\xec\x74\xcb\xcc\x41\x52\x9a\xd9\x12\x2b\xa0\xdd\xcc\xee\x10\x51\xd6\x2a\xf9\x11\x48\xf2\xd6\x62\x5b\xe4\xac\x39\xf3\x1d\xba\x54\x15\x52\x92\xc3\x4f\xb8\x6d\x43\xe1\xe1\x08\xe2\xc8\xe3\x12\xb4\xc6\x5d\x2e\xdf\xdd\xbb\x86\xa6\x75\x15\x73\x97\xe8\x89\x55\xcc\x6c\x5c\x17\x4d\xb0\x63\x1b\xde\x23\xd1\xdd\x4a\xac\x0b\xf4\xa8\x2a\x27\xdb\xcd\x0b\x3c\x07\xae\xa7\xa0\xa6\x81\x79\x85\x5e\x84\xb1\x03\xa3\x43\xba\xf1\x68\xfb\x93\x8f\x5f\x6f\x7f\x4a\xbf\x9a\x29\x69\x5d\xe9\xd9\xdf\x53\xdb\x39\x95\x0d\x76\xb1\x21\x75\xf1\x2b\x69\x7d\x95\x86\xee\xef\x0b\xc1\x5a\xd7\x53\xc4\x08\x79\x4a\x88\x2b\xe6\x7a\x0e\x7e\x0c\x1e\xbc\xf1\x0a\x16\xa0\x40\x10\xd0\xfd\x30\xb9\xc1\x02\xd7\x40\xbb\xe1\x4c\xbe\x55\xad\xff\xec\xa7\xa1\xfe\xa5\xbb\xf5\x96\xcb\xcd\x17\x9e\x7d\xab\xd8\x1a\x1b\xf8\x65\x30\xb4\xc0\x41\x2a\x97\x5e\xfd\xfe\xb6\x29\xf0\xe1\x21\x82\xe3\xf1\x7b\x14\x1e\xe3\x09\x06\x63\xb7\xaf\x1f\xba\x1d\x1a\xb9\x3d\x3c\x70\xeb\x27\x5d\x5d\x0b\xfb\xe0\xc0\xed\xe0\xb8\xed\x85\x7f\xad\x47\xc6\x6d\x3e\xaa\xac\x25\xb7\x4d\xd2\xfb\xd1\x8d\xc0\x0d\x23\x3e\xe2\xba\x70\xc1\x44\xfd\x46\xe0\x39\x07\x1a\x73\xc5\xff\x02\x00\x00\xff\xff\x2f\x4a\x45\x94\x94\x19\x00\x00")
+var _masterEtcOriginMasterMasterConfigYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc4\x59\x7b\x6f\x1b\xb9\x11\xff\x5f\x9f\x82\x48\x0f\xc8\x5d\xd1\x5d\xc9\xf6\xa5\x77\xb7\xc0\xa1\xf0\x39\x49\x63\x9c\x9d\xa8\x56\x52\x14\x68\x8a\x03\x45\x8e\x56\x8c\xb8\xe4\x1e\x1f\x8a\x15\xd7\xdf\xbd\x18\x92\xbb\x4b\xc9\x92\xe3\x3c\x0e\xb5\x0d\xc3\x1e\xfe\x66\x38\x9c\x17\x87\x23\xca\x1b\x61\xad\xd0\xea\x4c\xab\x85\xa8\xab\x11\x21\xad\xf4\xb5\xc8\xfe\x27\xe4\x17\x2f\x24\x7f\x0a\x0b\xea\xa5\xb3\x08\xc1\x6f\x16\x00\xde\x50\x27\xb4\xea\x88\x84\xd0\x56\xfc\x13\x0c\x4a\xac\xc8\xfa\xa8\x27\x83\x5a\x57\xe4\xdf\xff\xe9\xff\x5f\x09\xc5\xab\x6d\xc1\x71\xc7\x1e\x61\xc0\x6a\x6f\x18\xf4\x1b\xe2\x8f\x14\x8d\x70\xb6\x22\x37\xb7\x19\xd1\xc0\xef\x1e\x6c\x46\x0e\x62\x5f\xad\xc1\x18\xc1\xe1\x33\x15\xce\x14\xec\x25\x65\x1a\x4e\x35\x9f\x1a\xb0\xe0\x3e\x4f\x3a\x17\x96\xce\x25\x54\x64\x41\xa5\x85\x9e\x1c\x37\x4d\x06\x39\xdd\x76\x4d\x00\xe9\x16\x94\x5d\x8a\x85\x2b\x85\x1e\x9f\x37\xb4\x86\xa9\x96\x82\x6d\x3e\x4f\x0b\xb8\x06\xe6\xd1\x7d\x57\x5e\xe6\x76\x2e\x48\x43\x1d\x5b\x06\xf9\xa7\x4a\x69\x17\xc4\x65\x00\x84\xac\x60\x53\x11\x81\x10\x5b\x6e\xa9\xc5\x41\x6d\x8a\x5e\x74\xc6\x43\xc8\x9a\x4a\x0f\x15\x79\xec\x8c\x87\xc7\xd9\x8a\xa2\x0d\x54\x83\x3a\x05\x07\x25\x80\x67\x00\xad\xae\xf6\x85\x43\xd1\x47\x49\x45\x5a\xcd\xed\x81\xa5\x39\xc6\x43\xbe\x68\xe0\x1d\x30\x57\x11\xd4\x23\x23\xdb\x95\x68\x5f\x85\x9d\x64\xd0\xfd\x39\x15\xd2\x1b\xd8\xc1\x45\x27\x65\xc6\x4f\xfe\xa1\x75\x6d\xa0\xa6\x4e\x9b\x2c\x97\x8c\xbe\xde\x9c\x49\x01\xca\x9d\xab\x85\x46\x12\x21\x0c\x8c\x7b\x2e\xd0\xfb\x03\x4b\xb1\x30\x5a\xb9\x22\xe0\x4b\x66\x5c\x00\xae\x60\x73\x2f\x6e\x05\x9b\x11\x6d\xc5\x05\xac\x41\xda\x6a\x54\xa0\x6f\x77\x5c\x4d\xad\x05\x37\xe8\x03\xd7\x0e\x14\x2e\xce\x98\x11\x6d\x4c\xe6\x82\x8c\xc1\xb1\xb1\x36\xa2\x16\x6a\xdc\x50\xeb\xc0\x8c\x7b\x8f\x16\x54\x59\x31\x97\x50\x30\xea\xa8\xd4\x75\xc1\xb4\xb2\x5a\x42\xf9\x0e\x0d\x2a\x75\xad\xbd\x7b\x73\x75\x51\x91\x47\x8f\x46\x84\x44\xee\xa9\x9f\x4b\xc1\x02\x75\xe9\x5c\x6b\xab\xf1\xf8\xe6\x86\x94\xcf\xae\x1d\x18\x45\xe5\x65\x00\xbd\xd0\xd6\xa1\xdf\xc9\xed\x6d\x85\xab\x2f\x35\x87\x32\x2e\x95\x53\x6d\x1c\xb9\xc5\x1c\x6f\xbf\x92\xa8\x71\x52\x7b\x3c\x22\xc4\x82\x59\x0b\x55\x0f\x0e\x99\x0b\xc5\x4f\x39\x37\x60\x6d\x45\x1e\xf5\x12\x7e\x19\xc8\x7b\x65\x3e\xea\x99\x5f\x82\x7b\xaf\xcd\xaa\x22\x5b\xbc\x89\x1a\x0f\x92\xbb\x3d\x5a\xa9\x44\x3d\xc0\xf4\xde\x66\x21\x4c\xce\x4e\x93\x29\x33\xf7\x6f\xe3\xd1\xeb\x28\xaf\xa1\xd7\x57\xa9\xf2\x9d\xab\xe7\x52\xd4\x4b\x57\x91\xc9\xe8\xe6\xa6\x20\x62\x41\xca\x17\xd4\xbe\xa4\x0d\xf0\x33\x30\x4e\x2c\x04\xa3\x0e\x3a\x4d\xd4\x0e\x3d\xe5\x54\x91\xa9\x18\x20\xbf\xb1\x0c\x73\xbf\xdd\xfb\x53\x64\x6a\x7f\xb2\x8c\xee\x64\x51\xc3\x3e\xd3\x0b\x72\x2f\x5b\x38\x31\x28\xde\x9d\x2e\x5d\x07\xaf\x45\x03\xda\xbb\x19\x30\xad\xb8\x45\xcb\x50\xef\x96\x43\x2a\x24\xd8\x0b\xa0\x1c\x4c\xb5\xe3\x81\x2c\xcb\x0a\x46\x77\x5d\xa4\x9b\x46\xab\x97\x83\x8a\xc5\x81\x04\x0d\x8b\x70\xed\x0c\x8d\xbb\x4c\x0d\x2c\xc4\xf5\xc0\xf5\xaf\xe2\x0a\x1a\xed\xa0\x78\x86\x98\x22\xc0\x6b\xa3\x7d\x1b\xe1\x77\x71\x7f\xc7\xc5\x40\xf4\x16\x8d\xd1\xc0\x21\xe4\x1b\x0b\x66\xc4\xb4\x72\x46\x4b\x09\x59\x45\x02\x09\x6c\xb8\x1c\xa4\x66\x2b\x3c\x48\x45\x86\x84\x8f\xd1\x56\x0c\xcc\xb6\xcb\x19\x06\xb3\x98\x3a\x18\x53\x51\x80\x15\xb5\xea\xcc\x97\x87\x78\xc2\x17\x71\x7d\x5f\x74\xec\x20\xd0\xf7\xd9\x96\x15\x79\xfc\xe7\xc7\x23\xa6\x8d\x3d\x95\x52\xbf\x07\xfe\x2a\x94\xa7\x50\xe5\xbe\xfd\x9b\xf8\x6e\x3c\x3e\x3a\xfe\xe1\x6d\x39\x09\x3f\x47\xdf\x56\xff\x7d\xfb\xe1\xbb\x7e\x49\x6a\x46\xe5\x52\x5b\x97\xe8\x18\x20\x86\xaa\x1a\x48\xca\x60\xdb\x85\x4d\xa2\x9e\x4f\x03\xa5\xe3\xbf\xb9\x21\xff\xf0\xda\xc1\x25\x38\x4a\xbe\x7d\x73\x75\x81\x05\x86\x94\xdf\x91\xdb\xdb\x4c\x62\x0a\xb9\xbd\x4c\x65\x16\x9f\x87\x59\x56\x7e\x0e\x46\x81\x03\xfb\xb6\xe4\xf1\xd2\x4f\xe0\x7b\x10\x6f\x4b\xbb\x66\x6f\xcb\xed\xed\x9e\xbe\x9c\x3d\xd5\x0d\x15\x6a\xd8\xef\xae\x88\x9d\x85\xde\xe3\x87\x36\xbf\x0b\x08\x7b\x1f\x94\x7f\x2f\xec\x80\x4d\x7f\xed\xb9\x43\x68\x31\x38\x9f\x66\x66\xfe\x88\x26\x9f\x60\x85\x6d\xe0\xc1\x5a\x72\x68\xdf\x44\xe7\x2a\x75\xa9\xd5\xe8\x41\xb7\xc6\x8f\x93\x27\x27\xe9\xa2\x78\xd0\x35\x01\x8e\xf1\xed\x6e\x81\xd1\xbe\xfc\xe3\xe2\x50\x8c\x86\x54\xdb\x5a\x0e\xcc\x09\xd2\xa7\xda\x1e\x04\xa6\x1b\x21\xde\x60\xdf\x90\x25\xc2\x33\xc7\xf8\x25\x34\xf3\x94\x22\x58\xa4\xb2\x6b\x17\x0d\x15\xee\xbe\x21\xbc\x8f\x4f\x7e\xf8\x69\xa7\x06\xa3\x9e\x33\xa7\x0d\xad\x61\x30\xd6\x10\x25\x69\x29\xd6\xc2\x2a\x5b\x28\x85\xde\x07\xdc\x6e\x5c\x31\x12\x66\xe8\x91\x1d\x31\xbd\xa7\xa2\x94\x5d\x58\x2e\x44\x34\x5b\x9a\x2d\xb4\x69\xa8\xcb\x24\x9c\x8c\xb5\x85\xe2\x9b\x1b\xa6\x9b\x56\x2b\x50\xee\xb6\xfa\xe6\x66\x1d\x05\xe0\x0d\x23\xf1\x26\x73\x5d\xeb\x1e\xbb\xc1\x18\x48\xa9\x11\xc4\x23\x49\x70\x77\x1d\xc9\x68\x31\xf7\x8a\x4b\x38\xe4\xc3\xc4\x79\xbf\x1b\x77\x40\xd1\x93\xad\x36\xae\x22\x47\x93\xe3\x27\x93\xd1\x60\xc2\x5c\x2d\x54\x82\xb6\x02\xd3\x0c\xcc\xa9\xa9\x7d\x03\x0a\x1b\xc0\x3f\x61\x59\x66\x52\x7b\x8e\x25\x3f\x18\x25\x90\x52\x5b\x48\x3f\x78\x03\xf1\x77\x89\xeb\x39\xbe\x35\x7a\x2d\xc2\xed\x99\x38\x02\x2c\x14\x7a\xe3\x95\x13\x0d\xf4\x22\xd3\x7a\x2b\xec\xd8\x82\x73\x42\xd5\xb6\x5c\xfd\x68\xf1\x19\xb3\x3e\xa2\xb2\x5d\xd2\xa3\x9f\xfb\x2e\xdb\x46\xa7\x15\x73\xca\x56\xa0\x78\xc7\x0d\x8e\xf1\x93\x2d\x40\x03\x5c\xd0\xc2\x6d\x5a\x18\x76\x68\x25\xf6\x1a\x42\xab\xf1\x5a\xf1\x72\xb0\x45\xd9\x1a\xed\xf4\xdc\x2f\xd0\xf0\xfd\x45\xf3\x07\x1b\xa2\x49\x0e\xf0\xca\x85\xe6\x50\x82\xda\xba\x83\x3a\xc4\xf9\x34\x2b\x0a\x53\x23\x1a\x6a\x36\xe7\xd3\xd4\x02\x6b\xfe\x6c\x2d\xc2\xa5\x9d\x7a\x9a\x07\xbc\x2c\x52\xac\xa4\x0e\x26\x0f\xa7\xbb\x01\xb5\x05\x8a\xe1\x64\xd9\x12\xb8\xdf\xb6\x4f\x46\x8d\x11\x15\xcb\xcf\x9e\xc7\x43\x8f\x2b\xdf\x59\xad\x86\xee\xc1\xe2\xf1\xb0\x80\x5c\xe1\xb5\x9b\xfa\xdc\x6e\x6d\xe6\xe7\x0a\xa2\x95\xca\x74\x17\x64\x95\x91\x10\x8b\xaf\x50\x86\x12\x62\xe7\x85\xd3\x84\x64\xde\x70\x3c\xdb\xbd\x6f\xb0\xb0\x0f\xd7\xca\x59\x6a\xd9\x94\x8a\x7d\xcf\xce\x58\x80\x32\x06\x2d\x3e\x90\x1c\x28\xf7\x7a\xd3\xa2\xe0\x07\xc4\xd0\x5f\x72\x4c\x3a\x24\x21\x73\x6f\xac\xab\xc8\xf7\x93\xc9\x28\x3d\xc6\x3b\xa9\x0f\x12\x1a\x98\x7e\x6f\x6d\x45\x8e\x27\x93\xbd\x87\xc1\x63\xa5\x6c\x8e\xc6\xeb\x8b\xd6\x85\xd6\x2d\xa6\xcb\xff\xe1\xb8\x7f\xfd\xe2\xe3\x9e\x4c\x26\xfb\xce\x92\x9f\x76\xb7\x3d\x0d\x5a\xb2\x60\x8a\xd1\x57\x7e\x74\xaa\x18\x75\x69\x67\xac\x16\xd2\x23\x6f\x8a\xc6\xb3\xf3\xa7\x57\x31\x4a\xcf\xb6\xe8\xc8\xba\x8b\x4d\xef\x6b\x26\xb8\x39\xcc\x42\x08\xb6\xaa\x31\xfc\x2f\x40\xd5\x6e\x19\xb1\x2f\x76\xa8\x11\xdd\x05\xc5\xf9\x34\x09\x41\x7d\xd2\x3e\x93\x32\x7c\x8f\x27\xa3\x4f\x93\x99\x4e\x3c\x0d\x83\x3f\xcc\xae\x8a\x18\xe0\x4b\xea\x86\x06\xa8\xd0\x6b\x5b\x34\x5e\x3a\xe1\x40\x51\xe5\x86\xc4\xcd\xd4\x38\x94\xbc\x7a\xfb\xe5\x15\x66\x12\xd3\x3f\xe0\x61\x5f\x1b\xaa\xb2\x61\x07\x21\x0d\xb8\xa5\xe6\x15\xa1\xde\x61\x57\x20\x38\x28\x27\xdc\x66\x9a\xca\x75\xe7\x9e\x25\x95\x12\x54\x9d\x0f\x7a\xa4\xae\x85\xca\xfe\x6f\x68\xdb\x0a\x55\x5f\x26\x81\x4c\x52\xd1\xf4\x6f\x69\x8c\xb8\x96\x5a\xfb\x9e\xff\x86\x27\x0d\xf4\xe1\x4a\xb8\x67\x04\xb7\x38\x54\x41\x3b\x79\x09\x17\x9b\x8c\x17\xaf\xa7\x61\x93\xf0\x5b\x1b\x7e\xbe\x73\x9c\xe1\xbe\x39\xbd\xdb\x6b\x7c\xf5\xc9\x4c\x14\xf8\x55\x44\x59\xc8\xe6\x9c\xe9\x5d\x19\x49\x97\xf4\xfa\xb4\x86\xfe\x0d\x7f\xd2\xd5\x9a\xc4\x11\x63\xd5\x5a\x95\x13\x67\xc0\x0c\x38\x7b\xf8\x76\x8a\xbc\x85\x8d\xb8\x72\x43\x1b\x39\x22\xc4\xe9\x15\x6c\xa9\x80\x57\x83\xb5\xaf\x91\xbc\xa3\xc6\xf1\xf7\x47\x3f\x1d\x27\x4d\xd0\xe1\xda\x88\x0f\xb0\x0f\xf8\x64\x32\x19\xb5\xd4\x5b\x38\xcb\x5f\xb6\xb1\x71\x6c\xb3\xd9\x21\x9e\x79\xae\xb5\xb3\xce\xd0\x36\x4e\x74\x0f\xaa\x1f\xf9\xba\x9b\xb5\x4f\xd0\x73\xb5\x30\xd4\x3a\xe3\x99\xf3\x06\xd0\x30\xb6\xa5\x6c\xeb\x61\x2f\x10\x92\xf3\xcc\x96\xd4\x00\xef\x27\xab\xfb\x98\x46\xad\xd1\x38\x2b\x1d\xf4\x4c\x0f\x30\xac\x9c\xb3\x30\x4c\xd0\xa6\x22\x46\x4b\xf8\x99\xb6\x6d\x6c\x4c\x90\x21\x8d\xa6\x2e\xc1\x5a\xda\xdf\xf5\xdb\x6b\xaf\xa1\x69\xb1\x99\xee\x1b\x01\xe6\x8d\x70\x1b\x7c\xf0\x33\x9c\x75\xa6\x1c\x66\xb6\xa7\xa4\xbe\xc1\x4e\xaa\xf1\x71\xb7\x78\x41\xe7\x20\xed\x14\xa7\x2b\x41\x78\x45\x9e\x84\x25\x2f\xf8\x2e\xdf\xd1\xa4\xfb\x2a\x8e\x7e\xea\xbe\xc6\x81\x3a\x32\xda\x63\x57\x3a\x9c\xd3\xfa\x39\x0f\x8f\xc9\x58\xd5\xae\xe2\xfa\xac\xa3\x62\x5d\x4b\x15\xf0\x94\x31\xed\xf3\xc2\x13\x3e\xa5\x88\x61\x78\x05\x0b\x30\xa0\xf0\x63\x8c\x7e\xd6\xdf\x50\x45\x6b\xe0\xfd\x00\xa9\xe8\x6c\x1a\xfe\x0e\xc3\x6a\x30\x89\xde\x4a\xbd\xf9\x48\x66\xb7\x46\xac\xa9\x83\x5f\xbb\xae\x2e\x69\x45\xa3\x56\x78\x89\x87\xf5\x34\x37\x8c\x03\xd5\x04\x4e\xdb\xdf\xe1\x08\x98\xc0\xb0\x33\x2b\xfd\x82\x49\xe9\x83\x1e\xc0\x1f\x9b\x92\x0e\x13\xba\xf8\x57\xb1\x6b\x8c\x7b\x67\xa6\x7b\x27\xa6\x4f\x26\x0f\x98\x99\xee\x9d\x98\x7e\x95\x79\xe9\x97\x4f\x4b\x55\x3e\x88\xbc\x97\x25\x7f\xa5\x1f\x9c\x93\x86\x1a\xbb\xd6\xd2\x37\x5d\xf3\x35\x22\x84\x6f\x14\x6d\x04\x0b\x17\x27\x16\x4f\xa1\xea\x67\x0a\x3f\xc1\xe2\x15\x71\xc6\xc3\xe8\x7f\x03\x00\x2f\xfc\xc9\x5e\x48\x1c\x00\x00")
 
 func masterEtcOriginMasterMasterConfigYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -130,7 +130,7 @@ func masterEtcOriginMasterMasterConfigYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "master/etc/origin/master/master-config.yaml", size: 6548, mode: os.FileMode(420), modTime: time.Unix(1521079440, 0)}
+	info := bindataFileInfo{name: "master/etc/origin/master/master-config.yaml", size: 7240, mode: os.FileMode(420), modTime: time.Unix(1792316644, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -215,7 +215,7 @@ func masterEtcOriginMasterSessionSecretsYaml() (*asset, error) {
 	return a, nil
 }
 
-var _nodeEtcOriginNodeNodeConfigYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x54\xc1\x6e\xe3\x36\x10\xbd\xf3\x2b\x06\x9b\x3d\xb4\x40\x2d\xc9\x49\x83\x02\x04\x72\x48\x13\x6c\x37\x28\xb2\x4d\x37\x45\xef\x34\x39\xd2\xb2\xa6\x66\xd8\x21\xa9\x8d\x63\xf8\xdf\x0b\x4a\x76\x92\xf6\x54\xec\xc5\x80\xe7\xbd\x19\xbd\x79\x7c\xa4\x09\x81\xbf\xde\xfa\x64\x36\x01\xdd\x2d\xdb\x2d\x8a\x86\xde\x84\x84\xca\x44\xff\x27\x4a\xf2\x4c\x1a\xa6\xb5\x72\x94\x7e\xf6\xe4\xae\x9d\x13\x4c\x49\xc3\xfa\xfc\xa7\xa6\x6b\xba\x66\xad\x2f\x2f\x2a\x78\xcb\xa3\xf1\xa4\xc1\x86\x92\x32\x4a\x13\xd8\x9a\x50\x81\xbb\x07\x0d\xfb\x3d\x7c\xe7\xc9\xe1\x13\x34\x77\x0f\x09\xba\xef\x9b\xc7\x2c\x9e\x06\x38\x1c\x2a\xe5\x33\xda\x22\xc9\x4f\xf8\x19\x13\x87\xe9\x86\xa9\xd7\xd0\x62\xb6\x2d\x8b\x1f\x3c\xb5\xc4\x0e\x5b\x99\xc1\xc6\x32\xf5\xca\xcd\x52\x2b\xd1\x0f\x5a\x01\xe0\x13\xda\x8f\x86\x5c\x40\xf9\x64\x46\xd4\xf0\xee\x9d\xf2\xa3\x19\xf0\x95\xd2\xb3\x8c\x26\x6b\xe0\x88\x94\xbe\xf8\x3e\x5f\xb4\x9c\x70\xf5\x7e\x6f\x79\x8c\x4c\x48\xf9\xa0\xdf\xef\xa7\x65\xe5\x83\x02\x08\x26\x63\xca\x27\x3b\x7c\xcc\xd5\xa4\xf4\xb8\x23\xfb\x80\xe2\xd9\x69\xb8\xe8\x92\xda\x7a\x72\x1a\x3e\xb1\x3b\x7e\x4a\x6d\xcb\x06\x03\xe6\x6b\x19\xca\x88\x94\x93\x56\x67\x00\x36\x70\x71\x2b\x7b\x14\x73\x06\xb0\x5a\xf6\x33\xcf\x45\x70\xf9\x5d\x16\x7b\xe1\x46\xe1\xc9\x3b\x94\x23\x7b\xa6\x28\x80\xea\xc4\x2a\x98\x0d\x86\xa4\xd5\x7e\xbf\x02\xdf\x43\x73\x6f\xaa\xe5\x87\x2a\x7a\x05\xc2\x01\xaf\xc6\xb9\x32\x13\x30\x24\x7c\x0b\x99\x18\x97\x3a\xb9\x63\x39\xf0\x30\x78\x1a\xae\xb2\x14\x9c\x0b\xcf\x4c\x78\xe5\xb0\x37\x25\x64\xb5\x8c\xba\x09\x1e\x29\xdf\x30\x11\xda\xec\x99\x7e\x9b\x50\xc4\x3b\x4c\xd5\x5a\x63\x2d\xc6\x0a\x66\xa4\xfc\xc7\x2e\x62\xd2\x60\x62\x0c\xde\x9a\xca\x6d\x27\x72\x4d\xb5\x45\x08\x33\xa6\x26\x0a\x67\xde\x94\xfe\x87\xb7\x9c\xbf\x12\x93\x02\xd8\x14\xa9\x9e\x9f\x77\x9d\x02\xb0\xaf\x13\xff\xd7\x40\x05\xf0\x77\xac\xd9\xec\xba\xa3\xec\x5f\xcb\xe6\x14\x01\x48\xbb\x94\x71\xd4\xd5\x41\xbd\xdf\x43\xf3\x91\x53\x26\x33\x22\x1c\x0e\xf3\xb0\xe5\x74\x14\x61\xfe\xca\xb2\x7d\x0d\xce\x98\x8b\x86\xf5\x8f\x97\x55\xd1\x11\x7c\x08\x65\xf0\xb4\x24\x4d\xd0\x7d\x31\xb9\x7d\x89\xd5\x8a\xa7\xb4\x1a\x4b\xc8\x3e\x23\x19\xca\xea\x9b\x7a\xd8\xe1\x42\xfd\x8f\x52\x15\xd9\xdd\x1b\xf2\x3d\xa6\x7c\x92\x18\x85\x9f\x76\x6f\xf2\x06\x30\x57\x56\x63\xdd\x74\x3e\xd0\x53\x78\x55\x42\x99\x3c\x0d\x77\xd4\x73\x45\x36\x6f\x6f\x74\x37\xdf\xe7\x4e\xaf\xbb\xf3\x79\x57\x8b\x92\x3f\xf8\x80\x1a\x6a\x17\x4a\x63\x25\xd7\xf2\x92\x84\x6b\x0d\xd6\x1c\x4b\x5b\xdc\xfd\x8b\xb8\xc5\x9d\x3a\x9b\x38\x94\xf1\xe5\xfe\x9d\x01\xcc\x2f\xc2\xef\x85\xb3\x99\xff\x02\x44\x94\x0f\x8f\xbf\x08\x97\xa8\xe1\x72\x7d\x7e\xef\xd5\xd2\x73\xeb\x05\x6d\x66\xd9\x69\x68\x27\x23\x6d\xf0\x9b\xd3\x43\xf0\xe2\xd8\xf2\xbe\x34\x4b\x43\x52\xff\x04\x00\x00\xff\xff\xb4\x7d\x42\xa8\xc8\x04\x00\x00")
+var _nodeEtcOriginNodeNodeConfigYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x54\x4d\x6f\xe3\x36\x10\xbd\xf3\x57\x0c\x92\x3d\x56\x1f\x4e\x1a\x14\x20\x90\x43\x1a\x63\xbb\x41\x91\xd4\xdd\x14\xbd\xd3\xe4\x48\xcb\x9a\x9a\x51\x87\x94\x76\x1d\x43\xff\xbd\xa0\x64\x3b\x2e\xd0\x43\xb1\x17\x03\x7e\xf3\x38\x7c\xf3\xe6\x51\x26\x04\xfe\xba\xf6\xd1\x6c\x03\xba\x35\xdb\x1d\x8a\x86\xc6\x84\x88\xca\xf4\xfe\x4f\x94\xe8\x99\x34\x8c\x2b\xe5\x28\xfe\xec\xc9\x3d\x38\x27\x18\xa3\x86\xd5\xcd\x4f\x65\x5d\xd6\xe5\x4a\xdf\xdd\xe6\xe2\x9a\x3b\xe3\x49\xc3\xe1\x00\xe5\xfa\xe5\x75\xf9\x0b\xd3\x94\x6b\x4f\x9b\x05\x7f\x61\x87\xe5\x46\x7c\x67\x64\xff\xb4\x39\x16\x3f\xa3\x1d\x24\xfa\x11\x3f\x63\xe4\x30\x3e\x32\x35\x1a\x2a\x4c\xb6\x62\xf1\xad\xa7\x8a\xd8\x61\x25\x73\xb1\xb4\x4c\x8d\x72\xb3\xce\x4c\xf4\xad\x56\x00\xf8\x0d\xed\x27\x43\x2e\xa0\xbc\x98\x0e\x35\x5c\x5d\x29\xdf\x99\x16\xdf\x29\x0d\x4b\x67\x92\x06\xee\x91\xe2\x17\xdf\xa4\xdb\x8a\x23\x16\x1f\x0e\x96\xbb\x9e\x09\x29\x4d\xfa\xc3\x61\x5c\xe6\x9d\x14\x40\x30\x09\x63\x3a\x79\xe1\xfb\x94\x1d\x8a\xaf\x7b\xb2\x1b\x14\xcf\x4e\xc3\x6d\x1d\xd5\xce\x93\xd3\x90\xc7\x5a\xae\x52\xbb\x61\x8b\x01\xd3\x83\xb4\x43\x87\x94\xa2\x56\xd7\x00\x36\xf0\xe0\x0a\x7b\x14\x73\x0d\x50\x2c\xf3\x99\xb7\x41\x70\xf9\x5d\x06\x3b\x73\x7b\xe1\xd1\x3b\x94\x23\x7b\xa6\x28\x80\xec\x44\x11\xcc\x16\x43\xd4\xea\x70\x28\xc0\x37\x47\x53\x9f\x4d\x4c\x28\x53\x56\x5e\x80\x70\xc0\xfb\x6e\x46\x66\x16\x86\x88\x97\x25\xd3\xf7\x0b\x4e\xee\x08\x07\x6e\x5b\x4f\xed\x7d\x92\x01\x67\xe0\x8d\x09\xef\x1d\x36\x66\x08\x49\x2d\xad\x1e\x83\x47\x4a\x8f\x4c\x84\x36\x79\xa6\xdf\x46\x14\xf1\x0e\x63\xf6\xd7\x58\x8b\x7d\x2e\x26\xa4\xf4\xc7\xbe\xc7\xa8\xc1\xf4\x7d\xf0\xd6\x64\x6e\x35\x92\x2b\xb3\x37\x42\x98\x30\x96\xbd\x70\xe2\xed\xd0\xfc\x70\xc9\xf9\x2b\x32\x29\x80\xed\x20\xd9\xf8\x9b\xba\x56\x00\xf6\xbd\xe3\xff\x6a\xa8\x00\xfe\xee\x73\x3a\xeb\xfa\x28\xfb\xd7\x61\x7b\xca\x01\xc4\x7d\x4c\xd8\xe9\x6c\xa3\x3e\xe7\xf1\x13\xc7\x44\xa6\x43\x98\xa6\x59\x62\xde\x84\x6f\x15\x61\xfa\xca\xb2\x7b\x8f\x50\x97\x06\x0d\xab\x1f\xef\xb2\xac\x63\x71\x13\x86\xd6\xd3\x92\x39\x41\xf7\xc5\xa4\xea\x1c\xb0\x82\xc7\x58\x74\x43\x48\x3e\x21\x19\x4a\xea\xbb\xce\xb0\xc3\x85\xfa\x5f\x72\x55\xcf\xee\xd9\x90\x6f\x30\xa6\x93\xce\x5e\xf8\xdb\xfe\x22\x7e\x00\x33\x52\x74\x79\xe6\x79\xb5\xa7\x2c\xab\x88\x32\x7a\x6a\x9f\xa8\xe1\x3c\xdf\xf6\xf2\x75\x5f\x9d\xef\xbb\x78\xf4\xb0\xaa\x6f\xee\x6a\x98\xa6\xab\xbc\x19\x94\xf4\xd1\x07\xd4\x90\xfb\xa0\x94\x56\x52\x86\x97\x94\x3c\x68\xb0\xe6\x08\xed\x70\xff\x2f\xe2\x0e\xf7\xea\x7a\xe4\x30\x74\xa7\xc5\xe4\x94\x07\xb6\x26\xfc\x3e\x70\x32\x73\xe8\x01\x7a\x94\x8f\xaf\xbf\x08\x0f\xbd\x86\xbb\xd5\xcd\xb3\x57\xcb\x99\xb5\x17\xb4\x89\x65\xaf\xa1\x1a\x8d\x54\xc1\x6f\x4f\x5f\x8a\xb3\x91\xe5\xdc\xad\x1c\x39\x0c\x1d\x46\xf5\xcf\x00\x26\xad\xa7\x43\xe6\x04\x00\x00")
 
 func nodeEtcOriginNodeNodeConfigYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -230,12 +230,12 @@ func nodeEtcOriginNodeNodeConfigYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "node/etc/origin/node/node-config.yaml", size: 1224, mode: os.FileMode(384), modTime: time.Unix(1521078393, 0)}
+	info := bindataFileInfo{name: "node/etc/origin/node/node-config.yaml", size: 1254, mode: os.FileMode(384), modTime: time.Unix(1792316644, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
 
-var _nodeEtcOriginNodeNodeDnsmasqConf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2a\x4e\x2d\x2a\x4b\x2d\xb2\xd5\xcf\xcc\xd3\x4d\x4c\x49\x29\xd2\x4b\x2c\x2a\x48\xd4\x37\x34\x32\xd7\x33\xd0\x33\xd0\x33\xe4\x82\x49\x27\xe7\x94\x16\x97\xa4\x16\xe9\xe5\xe4\x27\x27\xe6\x20\xc9\x03\x02\x00\x00\xff\xff\x87\xc4\xbf\xc2\x3f\x00\x00\x00")
+var _nodeEtcOriginNodeNodeDnsmasqConf = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x42\x00\xbd\xff\x73\x65\x72\x76\x65\x72\x3d\x2f\x69\x6e\x2d\x61\x64\x64\x72\x2e\x61\x72\x70\x61\x2f\x31\x32\x37\x2e\x30\x2e\x30\x2e\x31\x0a\x73\x65\x72\x76\x65\x72\x3d\x2f\x7b\x7b\x20\x2e\x44\x4e\x53\x44\x6f\x6d\x61\x69\x6e\x20\x7d\x7d\x2f\x31\x32\x37\x2e\x30\x2e\x30\x2e\x31\x0a\x03\x00\xcc\x77\x1c\xd6\x42\x00\x00\x00")
 
 func nodeEtcOriginNodeNodeDnsmasqConfBytes() ([]byte, error) {
 	return bindataRead(
@@ -250,7 +250,7 @@ func nodeEtcOriginNodeNodeDnsmasqConf() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "node/etc/origin/node/node-dnsmasq.conf", size: 63, mode: os.FileMode(420), modTime: time.Unix(1521067701, 0)}
+	info := bindataFileInfo{name: "node/etc/origin/node/node-dnsmasq.conf", size: 66, mode: os.FileMode(420), modTime: time.Unix(1792314654, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -327,7 +327,7 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"master/etc/etcd/etcd.conf": masterEtcEtcdEtcdConf,
+	"etcd/etc/etcd/etcd.conf": etcdEtcEtcdEtcdConf,
 	"master/etc/origin/master/htpasswd": masterEtcOriginMasterHtpasswd,
 	"master/etc/origin/master/master-config.yaml": masterEtcOriginMasterMasterConfigYaml,
 	"master/etc/origin/master/openshift-ansible-catalog-console.js": masterEtcOriginMasterOpenshiftAnsibleCatalogConsoleJs,
@@ -379,11 +379,15 @@ type bintree struct {
 	Children map[string]*bintree
 }
 var _bintree = &bintree{nil, map[string]*bintree{
-	"master": &bintree{nil, map[string]*bintree{
+	"etcd": &bintree{nil, map[string]*bintree{
 		"etc": &bintree{nil, map[string]*bintree{
 			"etcd": &bintree{nil, map[string]*bintree{
-				"etcd.conf": &bintree{masterEtcEtcdEtcdConf, map[string]*bintree{}},
+				"etcd.conf": &bintree{etcdEtcEtcdEtcdConf, map[string]*bintree{}},
 			}},
+		}},
+	}},
+	"master": &bintree{nil, map[string]*bintree{
+		"etc": &bintree{nil, map[string]*bintree{
 			"origin": &bintree{nil, map[string]*bintree{
 				"master": &bintree{nil, map[string]*bintree{
 					"htpasswd": &bintree{masterEtcOriginMasterHtpasswd, map[string]*bintree{}},
@@ -1,4 +1,4 @@
 //go:generate go get -u github.com/go-bindata/go-bindata/...
-//go:generate go-bindata -pkg templates master/... node/...
+//go:generate go-bindata -pkg templates etcd/... master/... node/...
 
 package templates
@@ -2,6 +2,7 @@ package certgen
 
 import (
 	"bytes"
+	"crypto"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha1"
@@ -27,17 +28,21 @@ type generalName struct {
 	DirectoryName pkix.RDNSequence `asn1:"optional,explicit,tag:4"`
 }
 
-func newCertAndKey(filename string, template, signingcert *x509.Certificate, signingkey *rsa.PrivateKey, etcdcaspecial, etcdclientspecial bool) (CertAndKey, error) {
-	bits := 2048
-	if etcdcaspecial {
-		bits = 4096
-	}
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
 
-	key, err := rsa.GenerateKey(rand.Reader, bits)
+func newCertAndKey(filename string, template, signingcert *x509.Certificate, signingkey crypto.Signer, spec KeySpec, etcdcaspecial, etcdclientspecial bool) (CertAndKey, error) {
+	key, err := generateKey(spec.Algorithm)
 	if err != nil {
 		return CertAndKey{}, err
 	}
 
+	return newCert(template, signingcert, signingkey, key, etcdcaspecial, etcdclientspecial)
+}
+
+func newCert(template, signingcert *x509.Certificate, signingkey, key crypto.Signer, etcdcaspecial, etcdclientspecial bool) (CertAndKey, error) {
 	if signingcert == nil {
 		// make it self-signed
 		signingcert = template
@@ -45,11 +50,14 @@ func newCertAndKey(filename string, template, signingcert *x509.Certificate, sig
 	}
 
 	if etcdcaspecial {
-		template.SubjectKeyId = intsha1(key.N)
+		var err error
+		template.SubjectKeyId, err = keyId(key.Public())
+		if err != nil {
+			return CertAndKey{}, err
+		}
 		ext := pkix.Extension{
 			Id: []int{2, 5, 29, 35},
 		}
-		var err error
 		ext.Value, err = asn1.Marshal(authKeyId{
 			AuthorityCertIssuer:       generalName{DirectoryName: signingcert.Subject.ToRDNSequence()},
 			AuthorityCertSerialNumber: signingcert.SerialNumber,
@@ -62,13 +70,23 @@ func newCertAndKey(filename string, template, signingcert *x509.Certificate, sig
 	}
 
 	if etcdclientspecial {
-		template.SubjectKeyId = intsha1(key.N)
+		var err error
+		template.SubjectKeyId, err = keyId(key.Public())
+		if err != nil {
+			return CertAndKey{}, err
+		}
 		ext := pkix.Extension{
 			Id: []int{2, 5, 29, 35},
 		}
-		var err error
+		authorityKeyId := signingcert.SubjectKeyId
+		if authorityKeyId == nil {
+			authorityKeyId, err = keyId(signingkey.Public())
+			if err != nil {
+				return CertAndKey{}, err
+			}
+		}
 		ext.Value, err = asn1.Marshal(authKeyId{
-			KeyIdentifier:             intsha1(signingkey.N),
+			KeyIdentifier:             authorityKeyId,
 			AuthorityCertIssuer:       generalName{DirectoryName: signingcert.Subject.ToRDNSequence()},
 			AuthorityCertSerialNumber: signingcert.SerialNumber,
 		})
@@ -91,19 +109,21 @@ func newCertAndKey(filename string, template, signingcert *x509.Certificate, sig
 	return CertAndKey{cert: cert, key: key}, nil
 }
 
-func certAsBytes(cert *x509.Certificate) ([]byte, error) {
+func certAsBytes(certs ...*x509.Certificate) ([]byte, error) {
 	buf := &bytes.Buffer{}
 
-	err := pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
-	if err != nil {
-		return nil, err
+	for _, cert := range certs {
+		err := pem.Encode(buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return buf.Bytes(), nil
 }
 
-func writeCert(fs filesystem.Filesystem, filename string, cert *x509.Certificate) error {
-	b, err := certAsBytes(cert)
+func writeCert(fs filesystem.Filesystem, filename string, certs ...*x509.Certificate) error {
+	b, err := certAsBytes(certs...)
 	if err != nil {
 		return err
 	}
@@ -111,10 +131,15 @@ func writeCert(fs filesystem.Filesystem, filename string, cert *x509.Certificate
 	return fs.WriteFile(filename, b, 0666)
 }
 
-func privateKeyAsBytes(key *rsa.PrivateKey) ([]byte, error) {
+func privateKeyAsBytes(key crypto.Signer, encoding string) ([]byte, error) {
+	block, err := privateKeyBlock(key, encoding)
+	if err != nil {
+		return nil, err
+	}
+
 	buf := &bytes.Buffer{}
 
-	err := pem.Encode(buf, &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	err = pem.Encode(buf, block)
 	if err != nil {
 		return nil, err
 	}
@@ -122,8 +147,8 @@ func privateKeyAsBytes(key *rsa.PrivateKey) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-func writePrivateKey(fs filesystem.Filesystem, filename string, key *rsa.PrivateKey) error {
-	b, err := privateKeyAsBytes(key)
+func writePrivateKey(fs filesystem.Filesystem, filename string, key crypto.Signer, encoding string) error {
+	b, err := privateKeyAsBytes(key, encoding)
 	if err != nil {
 		return err
 	}
@@ -147,35 +172,126 @@ func writePublicKey(fs filesystem.Filesystem, filename string, key *rsa.PublicKe
 	return fs.WriteFile(filename, buf.Bytes(), 0666)
 }
 
-func (c *Config) PrepareMasterCerts(node *Node) error {
+// The last three CAs are only used by components with a dedicated CA and by a
+// generated named certificate.
+var CANames = []string{"ca", "frontproxy-ca", "master.etcd-ca", "service-signer", "logging-ca", "metrics-ca", "public-ca"}
+
+// Certificates signed by an existing CA are given random serial numbers, so as
+// not to collide with any it has already issued.
+func (c *Config) SetCA(name string, certPEM, keyPEM []byte) error {
+	ca, err := parseCA(name, certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+
 	if c.cas == nil {
 		c.cas = map[string]CertAndKey{}
 	}
+	c.cas[name] = ca
+	c.serial.random = true
 
-	if node.Master.certs == nil {
-		node.Master.certs = map[string]CertAndKey{}
+	if c.suppliedCAs == nil {
+		c.suppliedCAs = map[string]bool{}
 	}
+	c.suppliedCAs[name] = true
 
-	if node.Master.etcdcerts == nil {
-		node.Master.etcdcerts = map[string]CertAndKey{}
+	return nil
+}
+
+func parseCA(name string, certPEM, keyPEM []byte) (CertAndKey, error) {
+	var known bool
+	for _, caName := range CANames {
+		known = known || name == caName
+	}
+	if !known {
+		return CertAndKey{}, fmt.Errorf("unknown CA %q", name)
 	}
 
-	ips := append([]net.IP{}, node.IPs...)
-	ips = append(ips, net.ParseIP("172.30.0.1"))
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return CertAndKey{}, fmt.Errorf("CA %q: %v", name, err)
+	}
+	cert := certs[0]
 
-	dns := []string{
-		c.ExternalMasterHostname, "kubernetes", "kubernetes.default", "kubernetes.default.svc",
-		"kubernetes.default.svc.cluster.local", node.Hostname, "openshift",
-		"openshift.default", "openshift.default.svc",
-		"openshift.default.svc.cluster.local",
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return CertAndKey{}, fmt.Errorf("CA %q: certificate is not a CA certificate", name)
 	}
-	for _, ip := range ips {
-		dns = append(dns, ip.String())
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCertSign == 0 {
+		return CertAndKey{}, fmt.Errorf("CA %q: certificate key usage does not permit certificate signing", name)
+	}
+	if time.Now().After(cert.NotAfter) {
+		return CertAndKey{}, fmt.Errorf("CA %q: certificate expired at %s", name, cert.NotAfter.UTC().Format(time.RFC3339))
 	}
 
-	now := time.Now()
+	privkey, err := ParsePrivateKey(keyPEM)
+	if err != nil {
+		return CertAndKey{}, fmt.Errorf("CA %q: %v", name, err)
+	}
+	key, ok := privkey.(crypto.Signer)
+	if !ok {
+		return CertAndKey{}, fmt.Errorf("CA %q: unsupported private key type %T", name, privkey)
+	}
+
+	if !keyMatches(cert.PublicKey, key) {
+		return CertAndKey{}, fmt.Errorf("CA %q: private key does not match certificate", name)
+	}
+
+	return CertAndKey{cert: cert, key: key}, nil
+}
+
+// If the root CA's key is not given, every CA must be supplied with SetCA.
+func (c *Config) SetRootCA(certPEM, keyPEM []byte) error {
+	certs, err := ParseCertificates(certPEM)
+	if err != nil {
+		return fmt.Errorf("root CA: %v", err)
+	}
+	cert := certs[0]
+
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return fmt.Errorf("root CA: certificate is not a CA certificate")
+	}
+	if cert.MaxPathLenZero {
+		return fmt.Errorf("root CA: certificate path length constraint does not permit intermediate CAs")
+	}
+
+	root := &CertAndKey{cert: cert}
+
+	if keyPEM != nil {
+		privkey, err := ParsePrivateKey(keyPEM)
+		if err != nil {
+			return fmt.Errorf("root CA: %v", err)
+		}
+		key, ok := privkey.(crypto.Signer)
+		if !ok {
+			return fmt.Errorf("root CA: unsupported private key type %T", privkey)
+		}
+
+		if !keyMatches(cert.PublicKey, key) {
+			return fmt.Errorf("root CA: private key does not match certificate")
+		}
+
+		root.key = key
+	}
+
+	c.rootCA = root
+	c.serial.random = true
+
+	return nil
+}
+
+func (c *Config) caChain(name string) []*x509.Certificate {
+	chain := []*x509.Certificate{c.cas[name].cert}
+	if c.rootCA != nil {
+		chain = append(chain, c.rootCA.cert)
+	}
+	return chain
+}
 
-	cacerts := []struct {
+func (c *Config) caTemplates(now time.Time) []struct {
+	filename string
+	template *x509.Certificate
+} {
+	templates := []struct {
 		filename string
 		template *x509.Certificate
 	}{
@@ -205,25 +321,295 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 		},
 	}
 
-	for _, cacert := range cacerts {
+	for _, name := range ComponentNames {
+		if c.components[name] {
+			templates = append(templates, struct {
+				filename string
+				template *x509.Certificate
+			}{
+				filename: c.componentCA(name),
+				template: &x509.Certificate{
+					Subject: pkix.Name{CommonName: fmt.Sprintf("%s-signer@%d", name, now.Unix())},
+				},
+			})
+		}
+	}
+
+	if c.named != nil && c.named.generate {
+		templates = append(templates, struct {
+			filename string
+			template *x509.Certificate
+		}{
+			filename: "public-ca",
+			template: &x509.Certificate{
+				Subject: pkix.Name{CommonName: fmt.Sprintf("openshift-public-signer@%d", now.Unix())},
+			},
+		})
+	}
+
+	return templates
+}
+
+func (c *Config) PrepareCAs() error {
+	if c.cas == nil {
+		c.cas = map[string]CertAndKey{}
+	}
+
+	now := time.Now()
+
+	for _, cacert := range c.caTemplates(now) {
+		if _, exists := c.cas[cacert.filename]; exists {
+			continue
+		}
+
+		certAndKey, err := c.newCA(cacert.filename, cacert.template, now)
+		if err != nil {
+			return err
+		}
+
+		c.cas[cacert.filename] = certAndKey
+	}
+
+	if c.rootCA != nil {
+		for _, cacert := range c.caTemplates(now) {
+			name := cacert.filename
+			err := c.cas[name].cert.CheckSignatureFrom(c.rootCA.cert)
+			if err != nil {
+				return fmt.Errorf("CA %q is not signed by the root CA: %v", name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) newCA(filename string, template *x509.Certificate, now time.Time) (CertAndKey, error) {
+	var signingcert *x509.Certificate
+	var signingkey crypto.Signer
+	if c.rootCA != nil {
+		if c.rootCA.key == nil {
+			return CertAndKey{}, fmt.Errorf("CA %q must be supplied as the root CA's private key is not available", filename)
+		}
+		signingcert, signingkey = c.rootCA.cert, c.rootCA.key
+	}
+
+	serialNumber, err := c.serial.Get()
+	if err != nil {
+		return CertAndKey{}, err
+	}
+
+	catemplate := &x509.Certificate{
+		SerialNumber:          serialNumber,
+		NotBefore:             now,
+		NotAfter:              now.AddDate(5, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	catemplate.Subject = template.Subject
+
+	if c.rootCA != nil {
+		// intermediates only sign leaf certificates, and cannot outlive the
+		// root
+		catemplate.MaxPathLenZero = true
+		if catemplate.NotAfter.After(c.rootCA.cert.NotAfter) {
+			catemplate.NotAfter = c.rootCA.cert.NotAfter
+		}
+	}
+
+	return newCertAndKey(filename, catemplate, signingcert, signingkey, c.keySpec(caKeyClass(filename)), filename == "master.etcd-ca", false)
+}
+
+func (c *Config) PrepareCACSRs() error {
+	c.cacsrs = map[string]csrAndKey{}
+
+	for _, cacert := range c.caTemplates(time.Now()) {
+		if _, exists := c.cas[cacert.filename]; exists {
+			continue
+		}
+
+		key, err := generateKey(c.keySpec(caKeyClass(cacert.filename)).Algorithm)
+		if err != nil {
+			return err
+		}
+
+		// request a CA certificate which may only sign leaf certificates
+		ext := pkix.Extension{
+			Id:       []int{2, 5, 29, 19},
+			Critical: true,
+		}
+		ext.Value, err = asn1.Marshal(basicConstraints{IsCA: true, MaxPathLen: 0})
+		if err != nil {
+			return err
+		}
+
+		b, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+			Subject:         cacert.template.Subject,
+			ExtraExtensions: []pkix.Extension{ext},
+		}, key)
+		if err != nil {
+			return err
+		}
+
+		csr, err := x509.ParseCertificateRequest(b)
+		if err != nil {
+			return err
+		}
+
+		c.cacsrs[cacert.filename] = csrAndKey{csr: csr, key: key}
+	}
+
+	return nil
+}
+
+func (c *Config) WriteCACSRs(fs filesystem.Filesystem) error {
+	for filename, csr := range c.cacsrs {
+		buf := &bytes.Buffer{}
+		err := pem.Encode(buf, &pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr.csr.Raw})
+		if err != nil {
+			return err
+		}
+
+		err = fs.WriteFile(fmt.Sprintf("%s.csr", filename), buf.Bytes(), 0666)
+		if err != nil {
+			return err
+		}
+
+		err = writePrivateKey(fs, fmt.Sprintf("%s.key", filename), csr.key, c.keySpec(caKeyClass(filename)).Encoding)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const (
+	registryService = "docker-registry.default.svc"
+	registryPort    = 5000
+)
+
+func (c *Config) registryNames() []string {
+	names := []string{registryService, registryService + "." + c.DNSDomain}
+	if c.RegistryHostname != "" {
+		names = append(names, c.RegistryHostname)
+	}
+	return names
+}
+
+func masterKeyClass(filename string) string {
+	if filename == "openshift-router" {
+		return KeyClassRouter
+	}
+	return KeyClassMaster
+}
+
+// The router's and registry's certificates are deployed once for the whole
+// cluster, so every master is given the same ones.
+var clusterCertNames = []string{"openshift-router", "openshift-registry", "registry"}
+
+func (c *Config) PrepareClusterCerts() error {
+	if c.clusterCerts != nil {
+		return nil
+	}
+	c.clusterCerts = map[string]CertAndKey{}
+
+	now := time.Now()
+
+	certs := []struct {
+		filename string
+		template *x509.Certificate
+	}{
+		{
+			filename: "openshift-router",
+			template: &x509.Certificate{
+				Subject:     pkix.Name{CommonName: "*." + c.RoutingSubdomain()},
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+				DNSNames:    []string{"*." + c.RoutingSubdomain(), c.RoutingSubdomain()},
+			},
+		},
+		{
+			filename: "openshift-registry",
+			template: &x509.Certificate{
+				Subject:     pkix.Name{Organization: []string{"system:registries"}, CommonName: "system:openshift-registry"},
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			},
+		},
+		{
+			filename: "registry",
+			template: &x509.Certificate{
+				Subject:     pkix.Name{CommonName: registryService},
+				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+				DNSNames:    c.registryNames(),
+				IPAddresses: []net.IP{c.RegistryServiceIP()},
+			},
+		},
+	}
+
+	for _, cert := range certs {
 		template := &x509.Certificate{
-			SerialNumber:          c.serial.Get(),
 			NotBefore:             now,
-			NotAfter:              now.AddDate(5, 0, 0),
-			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+			NotAfter:              now.AddDate(2, 0, 0),
+			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
 			BasicConstraintsValid: true,
-			IsCA: true,
 		}
-		template.Subject = cacert.template.Subject
+		template.Subject = cert.template.Subject
+		template.ExtKeyUsage = cert.template.ExtKeyUsage
+		template.DNSNames = cert.template.DNSNames
+		template.IPAddresses = cert.template.IPAddresses
+		c.applyProfile(cert.filename, template, c.cas["ca"].cert, now)
 
-		certAndKey, err := newCertAndKey(cacert.filename, template, nil, nil, cacert.filename == "master.etcd-ca", false)
+		spec := c.keySpec(masterKeyClass(cert.filename))
+
+		if existing, found := c.existingClusterCerts[cert.filename]; found && c.reusable(existing, template, "ca", spec) {
+			c.clusterCerts[cert.filename] = existing
+			continue
+		}
+		serialNumber, err := c.serial.Get()
 		if err != nil {
 			return err
 		}
+		template.SerialNumber = serialNumber
 
-		c.cas[cacert.filename] = certAndKey
+		certAndKey, err := newCertAndKey(cert.filename, template, c.cas["ca"].cert, c.cas["ca"].key, spec, false, false)
+		if err != nil {
+			return err
+		}
+
+		c.clusterCerts[cert.filename] = certAndKey
+	}
+
+	return nil
+}
+
+func (c *Config) PrepareMasterCerts(node *Node) error {
+	err := c.PrepareCAs()
+	if err != nil {
+		return err
+	}
+
+	err = c.PrepareClusterCerts()
+	if err != nil {
+		return err
+	}
+
+	if node.Master.certs == nil {
+		node.Master.certs = map[string]CertAndKey{}
 	}
 
+	ips := append([]net.IP{}, node.IPs...)
+	ips = append(ips, c.KubernetesServiceIP())
+
+	dns := []string{
+		c.ExternalMasterHostname, "kubernetes", "kubernetes.default", "kubernetes.default.svc",
+		"kubernetes.default.svc." + c.DNSDomain, node.Hostname, "openshift",
+		"openshift.default", "openshift.default.svc",
+		"openshift.default.svc." + c.DNSDomain,
+	}
+	dns = append(dns, ipv4Names(ips)...)
+
+	now := time.Now()
+
 	certs := []struct {
 		filename string
 		template *x509.Certificate
@@ -246,7 +632,7 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 		{
 			filename: "etcd.server",
 			template: &x509.Certificate{
-				Subject:     pkix.Name{CommonName: node.IPs[0].String()},
+				Subject:     pkix.Name{CommonName: node.PrimaryIP().String()},
 				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 				DNSNames:    dns,
 				IPAddresses: ips,
@@ -258,7 +644,7 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 				Subject:     pkix.Name{CommonName: node.Hostname},
 				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 				DNSNames:    []string{node.Hostname}, // TODO
-				IPAddresses: []net.IP{node.IPs[0]},   // TODO
+				IPAddresses: node.IPs,
 			},
 			signer: "master.etcd-ca",
 		},
@@ -279,7 +665,7 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 		{
 			filename: "master.server",
 			template: &x509.Certificate{
-				Subject:     pkix.Name{CommonName: node.IPs[0].String()},
+				Subject:     pkix.Name{CommonName: node.PrimaryIP().String()},
 				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 				DNSNames:    dns,
 				IPAddresses: ips,
@@ -300,20 +686,10 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
 			},
 		},
-		{
-			filename: "openshift-router",
-			template: &x509.Certificate{
-				Subject:     pkix.Name{CommonName: fmt.Sprintf("*.%s.nip.io", c.ExternalRouterIP.String())},
-				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
-				DNSNames:    []string{fmt.Sprintf("*.%s.nip.io", c.ExternalRouterIP.String()), fmt.Sprintf("%s.nip.io", c.ExternalRouterIP.String())},
-			},
-		},
-		// TODO: registry cert?
 	}
 
 	for _, cert := range certs {
 		template := &x509.Certificate{
-			SerialNumber:          c.serial.Get(),
 			NotBefore:             now,
 			NotAfter:              now.AddDate(2, 0, 0),
 			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
@@ -323,12 +699,24 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 		template.ExtKeyUsage = cert.template.ExtKeyUsage
 		template.DNSNames = cert.template.DNSNames
 		template.IPAddresses = cert.template.IPAddresses
-
 		if cert.signer == "" {
 			cert.signer = "ca"
 		}
+		c.applyProfile(cert.filename, template, c.cas[cert.signer].cert, now)
+
+		spec := c.keySpec(masterKeyClass(cert.filename))
+
+		if existing, found := node.Master.existing[cert.filename]; found && c.reusable(existing, template, cert.signer, spec) {
+			node.Master.certs[cert.filename] = existing
+			continue
+		}
+		serialNumber, err := c.serial.Get()
+		if err != nil {
+			return err
+		}
+		template.SerialNumber = serialNumber
 
-		certAndKey, err := newCertAndKey(cert.filename, template, c.cas[cert.signer].cert, c.cas[cert.signer].key, false, cert.filename == "master.etcd-client")
+		certAndKey, err := newCertAndKey(cert.filename, template, c.cas[cert.signer].cert, c.cas[cert.signer].key, spec, false, cert.filename == "master.etcd-client")
 		if err != nil {
 			return err
 		}
@@ -336,6 +724,20 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 		node.Master.certs[cert.filename] = certAndKey
 	}
 
+	for filename, certAndKey := range c.clusterCerts {
+		node.Master.certs[filename] = certAndKey
+	}
+
+	return nil
+}
+
+func (c *Config) PrepareEtcdCerts(node *Node) error {
+	if node.Etcd.certs == nil {
+		node.Etcd.certs = map[string]CertAndKey{}
+	}
+
+	now := time.Now()
+
 	etcdcerts := []struct {
 		filename string
 		template *x509.Certificate
@@ -347,7 +749,7 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 				Subject:     pkix.Name{CommonName: node.Hostname},
 				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
 				DNSNames:    []string{node.Hostname}, // TODO
-				IPAddresses: []net.IP{node.IPs[0]},   // TODO
+				IPAddresses: node.IPs,
 			},
 			signer: "master.etcd-ca",
 		},
@@ -357,7 +759,7 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 				Subject:     pkix.Name{CommonName: node.Hostname},
 				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 				DNSNames:    []string{node.Hostname}, // TODO
-				IPAddresses: []net.IP{node.IPs[0]},   // TODO
+				IPAddresses: node.IPs,
 			},
 			signer: "master.etcd-ca",
 		},
@@ -365,7 +767,6 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 
 	for _, cert := range etcdcerts {
 		template := &x509.Certificate{
-			SerialNumber:          c.serial.Get(),
 			NotBefore:             now,
 			NotAfter:              now.AddDate(5, 0, 0),
 			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
@@ -375,27 +776,49 @@ func (c *Config) PrepareMasterCerts(node *Node) error {
 		template.ExtKeyUsage = cert.template.ExtKeyUsage
 		template.DNSNames = cert.template.DNSNames
 		template.IPAddresses = cert.template.IPAddresses
+		c.applyProfile("etcd/"+cert.filename, template, c.cas[cert.signer].cert, now)
+
+		spec := c.keySpec(KeyClassEtcd)
+
+		if existing, found := node.Etcd.existing[cert.filename]; found && c.reusable(existing, template, cert.signer, spec) {
+			node.Etcd.certs[cert.filename] = existing
+			continue
+		}
+		serialNumber, err := c.serial.Get()
+		if err != nil {
+			return err
+		}
+		template.SerialNumber = serialNumber
 
-		certAndKey, err := newCertAndKey(cert.filename, template, c.cas[cert.signer].cert, c.cas[cert.signer].key, false, true)
+		certAndKey, err := newCertAndKey(cert.filename, template, c.cas[cert.signer].cert, c.cas[cert.signer].key, spec, false, true)
 		if err != nil {
 			return err
 		}
 
-		node.Master.etcdcerts[cert.filename] = certAndKey
+		node.Etcd.certs[cert.filename] = certAndKey
 	}
 
 	return nil
 }
 
+// ipv4Names returns the IPv4 addresses in ips, for clients which ignore IP SANs.
+// IPv6 addresses are not valid DNS names.
+func ipv4Names(ips []net.IP) []string {
+	var names []string
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			names = append(names, ip.String())
+		}
+	}
+	return names
+}
+
 func (c *Config) PrepareNodeCerts(node *Node) error {
 	if node.certs == nil {
 		node.certs = map[string]CertAndKey{}
 	}
 
-	dns := []string{node.Hostname}
-	for _, ip := range node.IPs {
-		dns = append(dns, ip.String())
-	}
+	dns := append([]string{node.Hostname}, ipv4Names(node.IPs)...)
 
 	now := time.Now()
 
@@ -407,7 +830,7 @@ func (c *Config) PrepareNodeCerts(node *Node) error {
 		{
 			filename: "server",
 			template: &x509.Certificate{
-				Subject:     pkix.Name{CommonName: node.IPs[0].String()},
+				Subject:     pkix.Name{CommonName: node.PrimaryIP().String()},
 				ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 				DNSNames:    dns,
 				IPAddresses: node.IPs,
@@ -424,7 +847,6 @@ func (c *Config) PrepareNodeCerts(node *Node) error {
 
 	for _, cert := range certs {
 		template := &x509.Certificate{
-			SerialNumber:          c.serial.Get(),
 			NotBefore:             now,
 			NotAfter:              now.AddDate(2, 0, 0),
 			KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
@@ -434,12 +856,28 @@ func (c *Config) PrepareNodeCerts(node *Node) error {
 		template.ExtKeyUsage = cert.template.ExtKeyUsage
 		template.IPAddresses = cert.template.IPAddresses
 		template.DNSNames = cert.template.DNSNames
-
 		if cert.signer == "" {
 			cert.signer = "ca"
 		}
+		if cert.filename == "server" {
+			c.applyProfile("node/server", template, c.cas[cert.signer].cert, now)
+		} else {
+			c.applyProfile(cert.filename, template, c.cas[cert.signer].cert, now)
+		}
 
-		certAndKey, err := newCertAndKey(cert.filename, template, c.cas[cert.signer].cert, c.cas[cert.signer].key, false, false)
+		spec := c.keySpec(KeyClassNode)
+
+		if existing, found := node.existing[cert.filename]; found && c.reusable(existing, template, cert.signer, spec) {
+			node.certs[cert.filename] = existing
+			continue
+		}
+		serialNumber, err := c.serial.Get()
+		if err != nil {
+			return err
+		}
+		template.SerialNumber = serialNumber
+
+		certAndKey, err := newCertAndKey(cert.filename, template, c.cas[cert.signer].cert, c.cas[cert.signer].key, spec, false, false)
 		if err != nil {
 			return err
 		}
@@ -452,38 +890,44 @@ func (c *Config) PrepareNodeCerts(node *Node) error {
 
 func (c *Config) WriteMasterCerts(fs filesystem.Filesystem, node *Node) error {
 	for filename, ca := range c.cas {
-		err := writeCert(fs, fmt.Sprintf("etc/origin/master/%s.crt", filename), ca.cert)
+		certs := []*x509.Certificate{ca.cert}
+		if filename == "master.etcd-ca" {
+			// the master's etcd client trusts this file
+			certs = append(certs, c.rotatingCAs(filename)...)
+		}
+
+		err := writeCert(fs, fmt.Sprintf("etc/origin/master/%s.crt", filename), certs...)
 		if err != nil {
 			return err
 		}
 
-		err = writePrivateKey(fs, fmt.Sprintf("etc/origin/master/%s.key", filename), ca.key)
+		err = writePrivateKey(fs, fmt.Sprintf("etc/origin/master/%s.key", filename), ca.key, c.keySpec(caKeyClass(filename)).Encoding)
 		if err != nil {
 			return err
 		}
 	}
 
-	err := writeCert(fs, "etc/origin/master/ca-bundle.crt", c.cas["ca"].cert)
+	err := c.writeRotatingCAs(fs)
 	if err != nil {
 		return err
 	}
 
-	err = writeCert(fs, "etc/origin/master/client-ca-bundle.crt", c.cas["ca"].cert) // TODO: confirm if needed
+	err = writeCert(fs, "etc/origin/master/ca-bundle.crt", c.caBundle("ca")...)
 	if err != nil {
 		return err
 	}
 
-	err = writeCert(fs, "etc/origin/master/front-proxy-ca.crt", c.cas["frontproxy-ca"].cert) // TODO: confirm if needed
+	err = writeCert(fs, "etc/origin/master/client-ca-bundle.crt", c.caBundle("ca")...)
 	if err != nil {
 		return err
 	}
 
-	err = writePrivateKey(fs, "etc/origin/master/front-proxy-ca.key", c.cas["frontproxy-ca"].key) // TODO: confirm if needed
+	err = writeCert(fs, "etc/origin/master/front-proxy-ca.crt", append([]*x509.Certificate{c.cas["frontproxy-ca"].cert}, c.rotatingCAs("frontproxy-ca")...)...) // TODO: confirm if needed
 	if err != nil {
 		return err
 	}
 
-	err = writeCert(fs, "etc/etcd/ca.crt", c.cas["master.etcd-ca"].cert)
+	err = writePrivateKey(fs, "etc/origin/master/front-proxy-ca.key", c.cas["frontproxy-ca"].key, c.keySpec(KeyClassCA).Encoding) // TODO: confirm if needed
 	if err != nil {
 		return err
 	}
@@ -494,19 +938,28 @@ func (c *Config) WriteMasterCerts(fs filesystem.Filesystem, node *Node) error {
 			return err
 		}
 
-		err = writePrivateKey(fs, fmt.Sprintf("etc/origin/master/%s.key", filename), cert.key)
+		err = writePrivateKey(fs, fmt.Sprintf("etc/origin/master/%s.key", filename), cert.key, c.keySpec(masterKeyClass(filename)).Encoding)
 		if err != nil {
 			return err
 		}
 	}
 
-	for filename, cert := range node.Master.etcdcerts {
+	return c.writeNamedCertificate(fs)
+}
+
+func (c *Config) WriteEtcdCerts(fs filesystem.Filesystem, node *Node) error {
+	err := writeCert(fs, "etc/etcd/ca.crt", c.caBundle("master.etcd-ca")...)
+	if err != nil {
+		return err
+	}
+
+	for filename, cert := range node.Etcd.certs {
 		err := writeCert(fs, fmt.Sprintf("etc/etcd/%s.crt", filename), cert.cert)
 		if err != nil {
 			return err
 		}
 
-		err = writePrivateKey(fs, fmt.Sprintf("etc/etcd/%s.key", filename), cert.key)
+		err = writePrivateKey(fs, fmt.Sprintf("etc/etcd/%s.key", filename), cert.key, c.keySpec(KeyClassEtcd).Encoding)
 		if err != nil {
 			return err
 		}
@@ -517,7 +970,20 @@ func (c *Config) WriteMasterCerts(fs filesystem.Filesystem, node *Node) error {
 
 func (c *Config) WriteNodeCerts(fs filesystem.Filesystem, node *Node) error {
 	for _, filename := range []string{"ca", "node-client-ca"} {
-		err := writeCert(fs, fmt.Sprintf("etc/origin/node/%s.crt", filename), c.cas["ca"].cert)
+		err := writeCert(fs, fmt.Sprintf("etc/origin/node/%s.crt", filename), c.caBundle("ca")...)
+		if err != nil {
+			return err
+		}
+	}
+
+	// docker trusts the integrated registry by each of the names it is
+	// pulled from
+	registries := []string{hostPort(registryService, registryPort), hostPort(c.RegistryServiceIP(), registryPort)}
+	if c.RegistryHostname != "" {
+		registries = append(registries, c.RegistryHostname)
+	}
+	for _, registry := range registries {
+		err := writeCert(fs, fmt.Sprintf("etc/docker/certs.d/%s/ca.crt", registry), c.caBundle("ca")...)
 		if err != nil {
 			return err
 		}
@@ -529,7 +995,7 @@ func (c *Config) WriteNodeCerts(fs filesystem.Filesystem, node *Node) error {
 			return err
 		}
 
-		err = writePrivateKey(fs, fmt.Sprintf("etc/origin/node/%s.key", filename), cert.key)
+		err = writePrivateKey(fs, fmt.Sprintf("etc/origin/node/%s.key", filename), cert.key, c.keySpec(KeyClassNode).Encoding)
 		if err != nil {
 			return err
 		}
@@ -538,18 +1004,28 @@ func (c *Config) WriteNodeCerts(fs filesystem.Filesystem, node *Node) error {
 	return nil
 }
 
-func (c *Config) WriteMasterKeypair(fs filesystem.Filesystem, node *Node) error {
+func (c *Config) PrepareMasterKeypair(node *Node) error {
+	if c.serviceAccountKey != nil {
+		return nil
+	}
+
 	key, err := rsa.GenerateKey(rand.Reader, 2048)
 	if err != nil {
 		return err
 	}
 
-	err = writePrivateKey(fs, "etc/origin/master/serviceaccounts.private.key", key)
+	c.serviceAccountKey = key
+
+	return nil
+}
+
+func (c *Config) WriteMasterKeypair(fs filesystem.Filesystem, node *Node) error {
+	err := writePrivateKey(fs, "etc/origin/master/serviceaccounts.private.key", c.serviceAccountKey, "")
 	if err != nil {
 		return err
 	}
 
-	return writePublicKey(fs, "etc/origin/master/serviceaccounts.public.key", &key.PublicKey)
+	return writePublicKey(fs, "etc/origin/master/serviceaccounts.public.key", &c.serviceAccountKey.PublicKey)
 }
 
 func intsha1(n *big.Int) []byte {
@@ -0,0 +1,123 @@
+package filesystem
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v2"
+)
+
+const cloudConfigHeader = "#cloud-config\n"
+
+type cloudConfig struct {
+	WriteFiles []cloudConfigFile `yaml:"write_files"`
+}
+
+type cloudConfigFile struct {
+	Path        string `yaml:"path"`
+	Permissions string `yaml:"permissions"`
+	Owner       string `yaml:"owner"`
+	Encoding    string `yaml:"encoding"`
+	Content     string `yaml:"content"`
+}
+
+type cloudinitfile struct {
+	withPolicy
+	w        io.Writer
+	compress bool
+	config   cloudConfig
+}
+
+var _ Filesystem = &cloudinitfile{}
+
+// Files are written relative to / and base64 encoded, after gzip compression if
+// compress is set.
+func NewCloudInitFile(w io.Writer, compress bool) (Filesystem, error) {
+	return &cloudinitfile{w: w, compress: compress}, nil
+}
+
+// cloud-init looks owners up by name, so names are used where they are known.
+func cloudConfigOwner(owner Owner) string {
+	user, group := owner.User, owner.Group
+	if user == "" {
+		user = fmt.Sprint(owner.UID)
+	}
+	if group == "" {
+		group = fmt.Sprint(owner.GID)
+	}
+	return user + ":" + group
+}
+
+// cloud-init creates the directories of write_files itself, so a policy cannot
+// set their owners or modes.
+func (c *cloudinitfile) checkDirs(filename string) error {
+	if c.policy == nil {
+		return nil
+	}
+
+	for dir := path.Dir(path.Clean(filename)); dir != "." && dir != "/"; dir = path.Dir(dir) {
+		for _, rule := range c.policy.Rules {
+			if rule.matches(dir) && (rule.Owner != nil || rule.DirMode != 0) {
+				return fmt.Errorf("pattern %q: cloud-init cannot set the owner or mode of directory %s", rule.Pattern, dir)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *cloudinitfile) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	err := c.checkDirs(filename)
+	if err != nil {
+		return err
+	}
+
+	encoding := "b64"
+	if c.compress {
+		buf := &bytes.Buffer{}
+		gz := gzip.NewWriter(buf)
+		_, err := gz.Write(data)
+		if err != nil {
+			return err
+		}
+
+		err = gz.Close()
+		if err != nil {
+			return err
+		}
+
+		encoding, data = "gz+b64", buf.Bytes()
+	}
+
+	owner, mode := c.policy.archiveAttributes(filename, perm, false)
+
+	c.config.WriteFiles = append(c.config.WriteFiles, cloudConfigFile{
+		Path:        path.Join("/", filename),
+		Permissions: fmt.Sprintf("%04o", mode),
+		Owner:       cloudConfigOwner(owner),
+		Encoding:    encoding,
+		Content:     base64.StdEncoding.EncodeToString(data),
+	})
+
+	return nil
+}
+
+func (c *cloudinitfile) Close() error {
+	b, err := yaml.Marshal(&c.config)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(c.w, cloudConfigHeader)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.w.Write(b)
+	return err
+}
@@ -0,0 +1,27 @@
+package filesystem
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCloudInitDirectoryOwner(t *testing.T) {
+	for _, rule := range []PolicyRule{
+		{Pattern: "etc/etcd", Owner: &Owner{UID: 997, GID: 997}},
+		{Pattern: "etc", DirMode: 0700},
+	} {
+		fs, err := NewCloudInitFile(&bytes.Buffer{}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = SetPolicy(fs, &Policy{Rules: []PolicyRule{rule}})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if fs.WriteFile("etc/etcd/etcd.conf", nil, 0644) == nil {
+			t.Errorf("pattern %q: expected an error", rule.Pattern)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package filesystem
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+// cpio newc ("SVR4 with no CRC") mode bits.
+const (
+	cpioModeDir = 0040000
+	cpioModeReg = 0100000
+)
+
+const cpioTrailer = "TRAILER!!!"
+
+type cpiofile struct {
+	withPolicy
+	w    io.Writer
+	now  time.Time
+	dirs map[string]struct{}
+	ino  uint32
+}
+
+var _ Filesystem = &cpiofile{}
+
+// Entries' owners are recorded by UID and GID only.
+func NewCPIOFile(w io.Writer) (Filesystem, error) {
+	return &cpiofile{
+		w:    w,
+		now:  time.Now(),
+		dirs: map[string]struct{}{},
+	}, nil
+}
+
+// The header, name and data are each padded to a multiple of four bytes.
+func (c *cpiofile) writeEntry(name string, mode uint32, owner Owner, nlink uint32, data []byte) error {
+	var ino uint32
+	if name != cpioTrailer {
+		c.ino++
+		ino = c.ino
+	}
+
+	b := []byte(fmt.Sprintf("070701%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+		ino, mode, owner.UID, owner.GID, nlink, c.now.Unix(), len(data), 0, 0, 0, 0, len(name)+1, 0))
+	b = append(b, name...)
+	b = append(b, 0)
+	b = append(b, make([]byte, cpioPad(len(b)))...)
+	b = append(b, data...)
+	b = append(b, make([]byte, cpioPad(len(data)))...)
+
+	_, err := c.w.Write(b)
+	return err
+}
+
+func cpioPad(n int) int {
+	return (4 - n%4) % 4
+}
+
+func (c *cpiofile) mkdirAll(name string, perm os.FileMode) error {
+	for _, name := range missingDirs(c.dirs, name) {
+		owner, mode := c.policy.archiveAttributes(name, perm, true)
+
+		err := c.writeEntry(name, cpioModeDir|uint32(mode), owner, 2, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cpiofile) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	err := c.mkdirAll(path.Dir(filename), 0777)
+	if err != nil {
+		return err
+	}
+
+	owner, mode := c.policy.archiveAttributes(filename, perm, false)
+
+	return c.writeEntry(filename, cpioModeReg|uint32(mode), owner, 1, data)
+}
+
+func (c *cpiofile) Close() error {
+	return c.writeEntry(cpioTrailer, 0, Owner{}, 1, nil)
+}
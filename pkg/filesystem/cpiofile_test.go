@@ -0,0 +1,53 @@
+package filesystem
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+func TestCPIOPad(t *testing.T) {
+	for n, want := range []int{0, 3, 2, 1, 0, 3, 2, 1} {
+		if got := cpioPad(n); got != want {
+			t.Errorf("cpioPad(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestCPIOAlignment(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	fs, err := NewCPIOFile(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeTestFiles(t, fs)
+
+	b := buf.Bytes()
+	if len(b)%4 != 0 {
+		t.Errorf("archive length %d is not a multiple of four", len(b))
+	}
+
+	// each entry must start on a four byte boundary
+	for i := 0; i < len(b); {
+		if i%4 != 0 || !bytes.HasPrefix(b[i:], []byte("070701")) {
+			t.Fatalf("no entry at offset %d", i)
+		}
+
+		size, err := strconv.ParseUint(string(b[i+54:i+62]), 16, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+		namesize, err := strconv.ParseUint(string(b[i+94:i+102]), 16, 32)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b[i+110:i+110+int(namesize)-1]) == cpioTrailer {
+			break
+		}
+
+		n := 110 + int(namesize)
+		i += n + cpioPad(n) + int(size) + cpioPad(int(size))
+	}
+}
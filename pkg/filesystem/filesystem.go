@@ -3,10 +3,13 @@ package filesystem
 import (
 	"archive/tar"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"syscall"
 	"time"
@@ -24,48 +27,367 @@ type Filesystem interface {
 	Close() error
 }
 
+type aborter interface {
+	Abort() error
+}
+
+// Abort discards the output of fs after a failed write; fs must not then be
+// closed.
+func Abort(fs Filesystem) error {
+	if a, ok := fs.(aborter); ok {
+		return a.Abort()
+	}
+	return nil
+}
+
+// ManifestName lists the files written to a directory.  Directories without
+// one are only replaced or updated if forced.
+const ManifestName = ".certgen-files"
+
+type DirectoryOptions struct {
+	// InPlace replaces only the files listed in the previous manifest, removing
+	// those not written again, and leaves other files alone.
+	InPlace bool
+	Force   bool
+}
+
 type filesystem struct {
+	withPolicy
 	name string
+	// dir is the staging directory, or name if updating in place.
+	dir     string
+	opts    DirectoryOptions
+	dirs    map[string]struct{}
+	owned   map[string]struct{}
+	written map[string]struct{}
 }
 
 var _ Filesystem = &filesystem{}
 
-func NewFilesystem(name string) (Filesystem, error) {
-	err := os.RemoveAll(name)
+// Unless opts.InPlace is set, files are written to a staging directory
+// alongside name, which replaces name on Close.
+func NewFilesystem(name string, opts DirectoryOptions) (Filesystem, error) {
+	name = filepath.Clean(name)
+
+	owned, err := checkDirectory(name, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	err = os.MkdirAll(name, 0777)
+	if owned == nil {
+		owned = map[string]struct{}{}
+	}
+
+	f := &filesystem{
+		name:    name,
+		dir:     name,
+		opts:    opts,
+		dirs:    map[string]struct{}{},
+		owned:   owned,
+		written: map[string]struct{}{},
+	}
+
+	if opts.InPlace {
+		err = os.MkdirAll(name, 0777)
+		if err != nil {
+			return nil, err
+		}
+
+		return f, nil
+	}
+
+	err = os.MkdirAll(filepath.Dir(name), 0777)
 	if err != nil {
 		return nil, err
 	}
 
-	return &filesystem{name}, nil
+	f.dir, err = ioutil.TempDir(filepath.Dir(name), "."+filepath.Base(name)+".")
+	if err != nil {
+		return nil, err
+	}
+
+	// ioutil.TempDir creates the directory with mode 0700
+	err = os.Chmod(f.dir, 0777&^os.FileMode(umask))
+	if err != nil {
+		os.RemoveAll(f.dir)
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func CheckDirectory(name string, opts DirectoryOptions) error {
+	_, err := checkDirectory(filepath.Clean(name), opts)
+	return err
+}
+
+func checkDirectory(name string, opts DirectoryOptions) (map[string]struct{}, error) {
+	owned, ok, err := readManifest(name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok && !opts.Force {
+		return nil, fmt.Errorf("refusing to replace %s: it was not written by certgen (it has no %s)", name, ManifestName)
+	}
+	return owned, nil
+}
+
+// ok is false if name is a directory which is not empty and has no manifest.
+func readManifest(name string) (files map[string]struct{}, ok bool, err error) {
+	fi, err := os.Stat(name)
+	if os.IsNotExist(err) {
+		return nil, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if !fi.IsDir() {
+		return nil, false, &os.PathError{Op: "open", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(name, ManifestName))
+	if os.IsNotExist(err) {
+		infos, err := ioutil.ReadDir(name)
+		return nil, len(infos) == 0, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	files = map[string]struct{}{}
+	for _, filename := range strings.Split(string(b), "\n") {
+		if filename != "" {
+			files[filename] = struct{}{}
+		}
+	}
+
+	return files, true, nil
+}
+
+// AtomicFile is written alongside name and renamed into place on Close.
+type AtomicFile struct {
+	*os.File
+	name string
+}
+
+func CreateAtomic(name string, perm os.FileMode) (*AtomicFile, error) {
+	f, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".")
+	if err != nil {
+		return nil, err
+	}
+
+	// ioutil.TempFile creates the file with mode 0600
+	err = f.Chmod(perm &^ os.FileMode(umask))
+	if err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	return &AtomicFile{File: f, name: name}, nil
+}
+
+func (f *AtomicFile) Close() error {
+	err := f.File.Close()
+	if err == nil {
+		err = os.Rename(f.File.Name(), f.name)
+	}
+	if err != nil {
+		os.Remove(f.File.Name())
+	}
+	return err
+}
+
+func (f *AtomicFile) Abort() error {
+	f.File.Close()
+	return os.Remove(f.File.Name())
+}
+
+func writeFileAtomic(name string, data []byte, perm os.FileMode) error {
+	f, err := CreateAtomic(name, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		f.Abort()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (f *filesystem) apply(name string, perm os.FileMode, dir bool) error {
+	owner, mode := f.policy.attributes(name, perm, dir)
+
+	name = filepath.Join(f.dir, filepath.FromSlash(name))
+
+	err := os.Chmod(name, mode)
+	if err != nil || owner == nil {
+		return err
+	}
+
+	return os.Lchown(name, owner.UID, owner.GID)
 }
 
 func (f *filesystem) mkdirAll(name string, perm os.FileMode) error {
-	return os.MkdirAll(name, perm)
+	if f.policy == nil {
+		return os.MkdirAll(filepath.Join(f.dir, filepath.FromSlash(name)), perm)
+	}
+
+	for _, name := range missingDirs(f.dirs, name) {
+		err := os.Mkdir(filepath.Join(f.dir, filepath.FromSlash(name)), perm)
+		if err != nil && !os.IsExist(err) {
+			return err
+		}
+
+		err = f.apply(name, perm, true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (f *filesystem) WriteFile(filename string, data []byte, perm os.FileMode) error {
-	err := f.mkdirAll(filepath.Dir(filepath.Join(f.name, filename)), 0777)
+	err := f.mkdirAll(path.Dir(filename), 0777)
 	if err != nil {
 		return err
 	}
 
-	return ioutil.WriteFile(filepath.Join(f.name, filename), data, perm)
+	name := filepath.Join(f.dir, filepath.FromSlash(filename))
+
+	if f.opts.InPlace {
+		if _, owned := f.owned[filename]; !owned {
+			if !f.opts.Force {
+				_, err = os.Lstat(name)
+				if err == nil {
+					return fmt.Errorf("refusing to replace %s: it was not written by certgen", name)
+				}
+				if !os.IsNotExist(err) {
+					return err
+				}
+			}
+
+			// record the file before writing it, in case Close is never
+			// reached
+			f.owned[filename] = struct{}{}
+			err = f.writeManifest(f.owned)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = writeFileAtomic(name, data, perm)
+	} else {
+		err = ioutil.WriteFile(name, data, perm)
+	}
+	if err != nil {
+		return err
+	}
+	f.written[filename] = struct{}{}
+
+	if f.policy == nil {
+		return nil
+	}
+
+	return f.apply(filename, perm, false)
 }
 
-func (filesystem) Close() error {
+func (f *filesystem) Close() error {
+	if f.opts.InPlace {
+		for filename := range f.owned {
+			if _, written := f.written[filename]; written {
+				continue
+			}
+
+			err := os.Remove(filepath.Join(f.dir, filepath.FromSlash(filename)))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+
+			// remove any directories left empty, stopping at the first
+			// which is not
+			for dir := path.Dir(filename); dir != "."; dir = path.Dir(dir) {
+				if os.Remove(filepath.Join(f.dir, filepath.FromSlash(dir))) != nil {
+					break
+				}
+			}
+		}
+	}
+
+	err := f.writeManifest(f.written)
+	if f.opts.InPlace {
+		return err
+	}
+
+	if err == nil {
+		err = f.replace()
+	}
+	if err != nil {
+		os.RemoveAll(f.dir)
+	}
+	return err
+}
+
+// Files already updated in place are kept, and remain in the manifest.
+func (f *filesystem) Abort() error {
+	if f.opts.InPlace {
+		return nil
+	}
+	return os.RemoveAll(f.dir)
+}
+
+func (f *filesystem) writeManifest(files map[string]struct{}) error {
+	lines := make([]string, 0, len(files))
+	for filename := range files {
+		lines = append(lines, filename+"\n")
+	}
+	sort.Strings(lines)
+
+	return writeFileAtomic(filepath.Join(f.dir, ManifestName), []byte(strings.Join(lines, "")), 0666)
+}
+
+// A directory cannot be renamed over one which is not empty, so a previous
+// directory is first moved aside: between the renames, f.name does not exist.
+func (f *filesystem) replace() error {
+	var old string
+
+	_, err := os.Lstat(f.name)
+	switch {
+	case err == nil:
+		old = f.dir + ".old"
+		err = os.Rename(f.name, old)
+		if err != nil {
+			return err
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	err = os.Rename(f.dir, f.name)
+	if err != nil {
+		if old != "" {
+			os.Rename(old, f.name)
+		}
+		return err
+	}
+
+	if old != "" {
+		return os.RemoveAll(old)
+	}
 	return nil
 }
 
 type tgzfile struct {
+	withPolicy
 	gz   *gzip.Writer
 	tw   *tar.Writer
 	now  time.Time
 	dirs map[string]struct{}
+	// numericOwner is set to omit the owner's user and group names.
+	numericOwner bool
 }
 
 var _ Filesystem = &tgzfile{}
@@ -82,25 +404,53 @@ func NewTGZFile(r io.Writer) (Filesystem, error) {
 	return tw, nil
 }
 
-func (t *tgzfile) mkdirAll(name string, perm os.FileMode) error {
-	parts := strings.Split(name, "/")
-	for i := 1; i < len(parts); i++ {
-		name = filepath.Join(parts[:i]...)
-		if _, exists := t.dirs[name]; exists {
+// missingDirs returns name and its ancestors, outermost first, which are not
+// yet in dirs, and adds them to dirs.
+func missingDirs(dirs map[string]struct{}, name string) []string {
+	var missing []string
+
+	parts := strings.Split(path.Clean(name), "/")
+	for i := 1; i <= len(parts); i++ {
+		name = path.Join(parts[:i]...)
+		if _, exists := dirs[name]; exists || name == "." {
 			continue
 		}
-		err := t.tw.WriteHeader(&tar.Header{
-			Name:     name,
-			Mode:     int64(int(perm) &^ umask),
-			ModTime:  t.now,
-			Typeflag: tar.TypeDir,
-			Uname:    "root",
-			Gname:    "root",
-		})
+		dirs[name] = struct{}{}
+		missing = append(missing, name)
+	}
+
+	return missing
+}
+
+func (t *tgzfile) header(name string, perm os.FileMode, dir bool) *tar.Header {
+	owner, mode := t.policy.archiveAttributes(name, perm, dir)
+
+	h := &tar.Header{
+		Name:     name,
+		Mode:     int64(mode),
+		ModTime:  t.now,
+		Typeflag: tar.TypeReg,
+		Uid:      owner.UID,
+		Gid:      owner.GID,
+		Uname:    owner.User,
+		Gname:    owner.Group,
+	}
+	if dir {
+		h.Typeflag = tar.TypeDir
+	}
+	if t.numericOwner {
+		h.Uname, h.Gname = "", ""
+	}
+
+	return h
+}
+
+func (t *tgzfile) mkdirAll(name string, perm os.FileMode) error {
+	for _, name := range missingDirs(t.dirs, name) {
+		err := t.tw.WriteHeader(t.header(name, perm, true))
 		if err != nil {
 			return err
 		}
-		t.dirs[name] = struct{}{}
 	}
 	return nil
 }
@@ -111,15 +461,10 @@ func (t *tgzfile) WriteFile(filename string, data []byte, perm os.FileMode) erro
 		return err
 	}
 
-	err = t.tw.WriteHeader(&tar.Header{
-		Name:     filename,
-		Mode:     int64(int(perm) &^ umask),
-		Size:     int64(len(data)),
-		ModTime:  t.now,
-		Typeflag: tar.TypeReg,
-		Uname:    "root",
-		Gname:    "root",
-	})
+	h := t.header(filename, perm, false)
+	h.Size = int64(len(data))
+
+	err = t.tw.WriteHeader(h)
 	if err != nil {
 		return err
 	}
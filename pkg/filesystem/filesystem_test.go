@@ -0,0 +1,235 @@
+package filesystem
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeDirectory(name string, opts DirectoryOptions, filenames ...string) error {
+	fs, err := NewFilesystem(name, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, filename := range filenames {
+		err = fs.WriteFile(filename, []byte(filename), 0666)
+		if err != nil {
+			Abort(fs)
+			return err
+		}
+	}
+
+	return fs.Close()
+}
+
+// listDirectory returns the files below name, and checks that nothing but name
+// is left in its parent.
+func listDirectory(t *testing.T, name string) []string {
+	infos, err := ioutil.ReadDir(filepath.Dir(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		if info.Name() != filepath.Base(name) {
+			t.Errorf("%s left behind", info.Name())
+		}
+	}
+
+	r, err := NewFilesystemReader(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := r.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(name, ManifestName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest string
+	for _, filename := range files {
+		manifest += filename + "\n"
+	}
+	if string(b) != manifest {
+		t.Errorf("manifest %q, want %q", b, manifest)
+	}
+
+	return files
+}
+
+func tempDirectory(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "certgen-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.Join(dir, "out"), func() { os.RemoveAll(dir) }
+}
+
+func TestFilesystemReplace(t *testing.T) {
+	name, cleanup := tempDirectory(t)
+	defer cleanup()
+
+	err := writeDirectory(name, DirectoryOptions{}, "a", "etc/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := listDirectory(t, name); !reflect.DeepEqual(files, []string{"a", "etc/b"}) {
+		t.Errorf("wrote %v", files)
+	}
+
+	err = writeDirectory(name, DirectoryOptions{}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := listDirectory(t, name); !reflect.DeepEqual(files, []string{"a"}) {
+		t.Errorf("replaced with %v", files)
+	}
+}
+
+func TestFilesystemAbort(t *testing.T) {
+	name, cleanup := tempDirectory(t)
+	defer cleanup()
+
+	err := writeDirectory(name, DirectoryOptions{}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err := NewFilesystem(name, DirectoryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fs.WriteFile("b", nil, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Abort(fs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if files := listDirectory(t, name); !reflect.DeepEqual(files, []string{"a"}) {
+		t.Errorf("aborting left %v", files)
+	}
+}
+
+func TestFilesystemForeign(t *testing.T) {
+	name, cleanup := tempDirectory(t)
+	defer cleanup()
+
+	err := os.Mkdir(name, 0777)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an empty directory may be written to
+	err = CheckDirectory(name, DirectoryOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ioutil.WriteFile(filepath.Join(name, "foreign"), nil, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, opts := range []DirectoryOptions{{}, {InPlace: true}} {
+		err = writeDirectory(name, opts, "a")
+		if err == nil {
+			t.Errorf("%+v: replaced a directory without a manifest", opts)
+		}
+	}
+
+	err = writeDirectory(name, DirectoryOptions{Force: true}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := listDirectory(t, name); !reflect.DeepEqual(files, []string{"a"}) {
+		t.Errorf("forced replacement wrote %v", files)
+	}
+}
+
+func TestFilesystemInPlace(t *testing.T) {
+	name, cleanup := tempDirectory(t)
+	defer cleanup()
+
+	err := writeDirectory(name, DirectoryOptions{}, "a", "etc/b", "etc/c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.MkdirAll(filepath.Join(name, "var"), 0777)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(name, "var/foreign"), nil, 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// files which were not written by certgen are not replaced
+	err = writeDirectory(name, DirectoryOptions{InPlace: true}, "a", "var/foreign")
+	if err == nil {
+		t.Error("replaced a file not written by certgen")
+	}
+
+	// files which certgen wrote but no longer writes are removed, along with
+	// directories left empty
+	err = writeDirectory(name, DirectoryOptions{InPlace: true}, "a", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewFilesystemReader(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := r.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"a", "d", "var/foreign"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("updated in place to %v, want %v", files, want)
+	}
+	if _, err := os.Stat(filepath.Join(name, "etc")); !os.IsNotExist(err) {
+		t.Errorf("etc was not removed: %v", err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(name, ManifestName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "a\nd\n" {
+		t.Errorf("manifest %q, want %q", b, "a\nd\n")
+	}
+}
+
+func TestFilesystemReplaceRollback(t *testing.T) {
+	name, cleanup := tempDirectory(t)
+	defer cleanup()
+
+	err := writeDirectory(name, DirectoryOptions{}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the staging directory is missing, so it cannot be renamed into place
+	f := &filesystem{name: name, dir: name + ".staging"}
+	err = f.replace()
+	if err == nil {
+		t.Fatal("replaced with a missing directory")
+	}
+
+	if files := listDirectory(t, name); !reflect.DeepEqual(files, []string{"a"}) {
+		t.Errorf("rolled back to %v", files)
+	}
+}
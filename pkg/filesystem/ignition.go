@@ -0,0 +1,137 @@
+package filesystem
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+)
+
+// Ignition config spec versions.
+const (
+	IgnitionV2 = "2.2.0"
+	IgnitionV3 = "3.0.0"
+)
+
+type ignitionConfig struct {
+	Ignition struct {
+		Version string `json:"version"`
+	} `json:"ignition"`
+	Storage struct {
+		Directories []ignitionDirectory `json:"directories,omitempty"`
+		Files       []ignitionFile      `json:"files,omitempty"`
+	} `json:"storage"`
+}
+
+// Existing directories are not overwritten; Ignition only sets their owner and
+// mode.
+type ignitionDirectory struct {
+	Filesystem string     `json:"filesystem,omitempty"`
+	Path       string     `json:"path"`
+	Mode       int        `json:"mode"`
+	User       ignitionID `json:"user"`
+	Group      ignitionID `json:"group"`
+}
+
+// Filesystem is only used by spec v2, and Overwrite by spec v3.
+type ignitionFile struct {
+	Filesystem string `json:"filesystem,omitempty"`
+	Path       string `json:"path"`
+	Overwrite  *bool  `json:"overwrite,omitempty"`
+	Contents   struct {
+		Source string `json:"source"`
+	} `json:"contents"`
+	Mode  int        `json:"mode"`
+	User  ignitionID `json:"user"`
+	Group ignitionID `json:"group"`
+}
+
+type ignitionID struct {
+	ID   *int   `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+func newIgnitionID(id int, name string) ignitionID {
+	if name != "" {
+		return ignitionID{Name: name}
+	}
+	return ignitionID{ID: &id}
+}
+
+type ignitionfile struct {
+	withPolicy
+	w      io.Writer
+	dirs   map[string]struct{}
+	config ignitionConfig
+}
+
+var _ Filesystem = &ignitionfile{}
+
+// Files are written relative to / of the root filesystem, embedded as data URLs.
+func NewIgnitionFile(w io.Writer, version string) (Filesystem, error) {
+	switch version {
+	case IgnitionV2, IgnitionV3:
+	default:
+		return nil, fmt.Errorf("unsupported Ignition spec version %q", version)
+	}
+
+	i := &ignitionfile{w: w, dirs: map[string]struct{}{}}
+	i.config.Ignition.Version = version
+
+	return i, nil
+}
+
+func (i *ignitionfile) mkdirAll(name string, perm os.FileMode) {
+	for _, name := range missingDirs(i.dirs, name) {
+		owner, mode := i.policy.archiveAttributes(name, perm, true)
+
+		dir := ignitionDirectory{
+			Path:  path.Join("/", name),
+			Mode:  int(mode),
+			User:  newIgnitionID(owner.UID, owner.User),
+			Group: newIgnitionID(owner.GID, owner.Group),
+		}
+		if i.config.Ignition.Version == IgnitionV2 {
+			dir.Filesystem = "root"
+		}
+
+		i.config.Storage.Directories = append(i.config.Storage.Directories, dir)
+	}
+}
+
+func (i *ignitionfile) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	i.mkdirAll(path.Dir(filename), 0777)
+
+	owner, mode := i.policy.archiveAttributes(filename, perm, false)
+
+	file := ignitionFile{
+		Path:  path.Join("/", filename),
+		Mode:  int(mode),
+		User:  newIgnitionID(owner.UID, owner.User),
+		Group: newIgnitionID(owner.GID, owner.Group),
+	}
+	file.Contents.Source = "data:;base64," + base64.StdEncoding.EncodeToString(data)
+
+	if i.config.Ignition.Version == IgnitionV2 {
+		file.Filesystem = "root"
+	} else {
+		overwrite := true
+		file.Overwrite = &overwrite
+	}
+
+	i.config.Storage.Files = append(i.config.Storage.Files, file)
+
+	return nil
+}
+
+func (i *ignitionfile) Close() error {
+	b, err := json.MarshalIndent(&i.config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = i.w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,49 @@
+package filesystem
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestIgnitionDirectories(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	fs, err := NewIgnitionFile(buf, IgnitionV3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SetPolicy(fs, &Policy{Umask: 0022, Rules: []PolicyRule{{Pattern: "etc/etcd", Owner: &Owner{UID: 997, GID: 997, User: "etcd"}, DirMode: 0700}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, filename := range []string{"etc/etcd/ca.crt", "etc/etcd/etcd.conf"} {
+		err = fs.WriteFile(filename, nil, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = fs.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var config ignitionConfig
+	err = json.Unmarshal(buf.Bytes(), &config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gid := 997
+	want := []ignitionDirectory{
+		{Path: "/etc", Mode: 0755, User: ignitionID{Name: "root"}, Group: ignitionID{Name: "root"}},
+		{Path: "/etc/etcd", Mode: 0700, User: ignitionID{Name: "etcd"}, Group: ignitionID{ID: &gid}},
+	}
+	if !reflect.DeepEqual(config.Storage.Directories, want) {
+		t.Errorf("directories %+v, want %+v", config.Storage.Directories, want)
+	}
+}
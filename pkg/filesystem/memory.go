@@ -0,0 +1,168 @@
+package filesystem
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MemoryFilesystem holds the files written to it in memory, for callers to read
+// back.
+type MemoryFilesystem struct {
+	withPolicy
+	files map[string]*memoryFile
+}
+
+var _ Filesystem = &MemoryFilesystem{}
+var _ Reader = &MemoryFilesystem{}
+
+type memoryFile struct {
+	data  []byte
+	mode  os.FileMode
+	owner *Owner
+}
+
+func NewMemoryFilesystem() *MemoryFilesystem {
+	return &MemoryFilesystem{files: map[string]*memoryFile{}}
+}
+
+// Without a policy, perm is recorded as given: the umask is not applied.
+func (m *MemoryFilesystem) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	file := &memoryFile{data: append([]byte{}, data...), mode: perm}
+	if m.policy != nil {
+		file.owner, file.mode = m.policy.attributes(filename, perm, false)
+	}
+
+	m.files[path.Clean(filename)] = file
+
+	return nil
+}
+
+func (*MemoryFilesystem) Close() error {
+	return nil
+}
+
+func (m *MemoryFilesystem) Files() ([]string, error) {
+	files := make([]string, 0, len(m.files))
+	for filename := range m.files {
+		files = append(files, filename)
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func (m *MemoryFilesystem) ReadFile(filename string) ([]byte, error) {
+	file, exists := m.files[path.Clean(filename)]
+	if !exists {
+		return nil, &os.PathError{Op: "open", Path: filename, Err: os.ErrNotExist}
+	}
+
+	return append([]byte{}, file.data...), nil
+}
+
+func (m *MemoryFilesystem) Open(filename string) (io.ReadSeeker, error) {
+	file, exists := m.files[path.Clean(filename)]
+	if !exists {
+		return nil, &os.PathError{Op: "open", Path: filename, Err: os.ErrNotExist}
+	}
+
+	return bytes.NewReader(file.data), nil
+}
+
+// Directories exist wherever a file has been written below them.  Sys returns
+// the *Owner set by the policy, or nil.
+func (m *MemoryFilesystem) Stat(name string) (os.FileInfo, error) {
+	name = path.Clean(name)
+
+	if file, exists := m.files[name]; exists {
+		return &memoryFileInfo{name: path.Base(name), size: int64(len(file.data)), mode: file.mode, owner: file.owner}, nil
+	}
+
+	for filename := range m.files {
+		if name == "." || strings.HasPrefix(filename, name+"/") {
+			return &memoryFileInfo{name: path.Base(name), mode: os.ModeDir | 0777}, nil
+		}
+	}
+	if name == "." {
+		return &memoryFileInfo{name: name, mode: os.ModeDir | 0777}, nil
+	}
+
+	return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// Walk visits the root, ".", then each directory and file in lexical order.
+// If fn returns filepath.SkipDir for a directory, its contents are skipped.
+func (m *MemoryFilesystem) Walk(fn func(name string, info os.FileInfo) error) error {
+	info, err := m.Stat(".")
+	if err != nil {
+		return err
+	}
+
+	err = fn(".", info)
+	if err == filepath.SkipDir {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	dirs := map[string]struct{}{}
+	var names []string
+	for filename := range m.files {
+		names = append(names, missingDirs(dirs, path.Dir(filename))...)
+		names = append(names, filename)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// sort by path element, so that a directory's contents follow it
+		return strings.Replace(names[i], "/", "\x00", -1) < strings.Replace(names[j], "/", "\x00", -1)
+	})
+
+	var skip string
+	for _, name := range names {
+		if skip != "" && strings.HasPrefix(name, skip) {
+			continue
+		}
+
+		info, err := m.Stat(name)
+		if err != nil {
+			return err
+		}
+
+		err = fn(name, info)
+		if err == filepath.SkipDir && info.IsDir() {
+			skip = name + "/"
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type memoryFileInfo struct {
+	name  string
+	size  int64
+	mode  os.FileMode
+	owner *Owner
+}
+
+func (fi *memoryFileInfo) Name() string       { return fi.name }
+func (fi *memoryFileInfo) Size() int64        { return fi.size }
+func (fi *memoryFileInfo) Mode() os.FileMode  { return fi.mode }
+func (fi *memoryFileInfo) ModTime() time.Time { return time.Time{} }
+func (fi *memoryFileInfo) IsDir() bool        { return fi.mode.IsDir() }
+
+func (fi *memoryFileInfo) Sys() interface{} {
+	if fi.owner == nil {
+		return nil
+	}
+	return fi.owner
+}
@@ -0,0 +1,126 @@
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newTestMemoryFilesystem(t *testing.T) *MemoryFilesystem {
+	m := NewMemoryFilesystem()
+
+	err := SetPolicy(m, &Policy{
+		Rules: []PolicyRule{
+			{Pattern: "etc/etcd", Owner: &Owner{UID: 997, GID: 997}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// "etc/a-b" sorts before "etc/a/..." lexically, but after it by path
+	// element
+	for _, filename := range []string{"etc/etcd/etcd.conf", "etc/a-b", "etc/a/c", "z"} {
+		err = m.WriteFile(filename, []byte(filename), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return m
+}
+
+func TestMemoryFilesystemStat(t *testing.T) {
+	m := newTestMemoryFilesystem(t)
+
+	files, err := m.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"etc/a-b", "etc/a/c", "etc/etcd/etcd.conf", "z"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("Files() = %v, want %v", files, want)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		wantName  string
+		wantMode  os.FileMode
+		wantSize  int64
+		wantOwner bool
+	}{
+		{name: ".", wantName: ".", wantMode: os.ModeDir | 0777},
+		{name: "etc/", wantName: "etc", wantMode: os.ModeDir | 0777},
+		{name: "etc/a", wantName: "a", wantMode: os.ModeDir | 0777},
+		{name: "etc/a/c", wantName: "c", wantMode: 0600, wantSize: 7},
+		{name: "z", wantName: "z", wantMode: 0600, wantSize: 1},
+		{name: "etc/etcd/etcd.conf", wantName: "etcd.conf", wantMode: 0600, wantSize: 18, wantOwner: true},
+	} {
+		info, err := m.Stat(tt.name)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if info.Name() != tt.wantName || info.Mode() != tt.wantMode || info.Size() != tt.wantSize {
+			t.Errorf("%s: got %s %v %d, want %s %v %d", tt.name, info.Name(), info.Mode(), info.Size(), tt.wantName, tt.wantMode, tt.wantSize)
+		}
+		if owner, ok := info.Sys().(*Owner); ok != tt.wantOwner || (ok && owner.UID != 997) {
+			t.Errorf("%s: Sys() = %#v", tt.name, info.Sys())
+		}
+	}
+
+	for _, name := range []string{"e", "etc/a-", "z/y"} {
+		if _, err := m.Stat(name); !os.IsNotExist(err) {
+			t.Errorf("%s: Stat returned %v", name, err)
+		}
+	}
+
+	if _, err := NewMemoryFilesystem().Stat("."); err != nil {
+		t.Errorf("empty filesystem: %v", err)
+	}
+}
+
+func TestMemoryFilesystemWalk(t *testing.T) {
+	m := newTestMemoryFilesystem(t)
+
+	for _, tt := range []struct {
+		skip string
+		want []string
+	}{
+		{
+			want: []string{".", "etc", "etc/a", "etc/a/c", "etc/a-b", "etc/etcd", "etc/etcd/etcd.conf", "z"},
+		},
+		{
+			skip: "etc/a",
+			want: []string{".", "etc", "etc/a", "etc/a-b", "etc/etcd", "etc/etcd/etcd.conf", "z"},
+		},
+		{
+			skip: ".",
+			want: []string{"."},
+		},
+		{
+			// SkipDir for a file is an error
+			skip: "z",
+			want: []string{".", "etc", "etc/a", "etc/a/c", "etc/a-b", "etc/etcd", "etc/etcd/etcd.conf", "z"},
+		},
+	} {
+		var walked []string
+		err := m.Walk(func(name string, info os.FileInfo) error {
+			walked = append(walked, name)
+			if name == tt.skip {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if tt.skip == "z" {
+			if err != filepath.SkipDir {
+				t.Errorf("skip %q: Walk returned %v", tt.skip, err)
+			}
+		} else if err != nil {
+			t.Errorf("skip %q: %v", tt.skip, err)
+		}
+		if !reflect.DeepEqual(walked, tt.want) {
+			t.Errorf("skip %q: walked %v, want %v", tt.skip, walked, tt.want)
+		}
+	}
+}
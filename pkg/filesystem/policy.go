@@ -0,0 +1,125 @@
+package filesystem
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// User and Group, where a backend can record them, are preferred to UID and
+// GID when the output is unpacked.
+type Owner struct {
+	UID, GID    int
+	User, Group string
+}
+
+// rootOwner owns what no rule gives an owner, except in directory output.
+var rootOwner = Owner{User: "root", Group: "root"}
+
+// A PolicyRule whose Pattern (see path.Match) matches a directory applies to
+// everything below it too.
+type PolicyRule struct {
+	Pattern       string
+	Owner         *Owner
+	Mode, DirMode os.FileMode
+}
+
+// Where several rules match a name, later rules take precedence.
+type Policy struct {
+	// Umask applies where no rule gives a mode.
+	Umask os.FileMode
+	Rules []PolicyRule
+}
+
+func (p *Policy) Validate() error {
+	for _, rule := range p.Rules {
+		_, err := path.Match(rule.Pattern, "")
+		if err != nil {
+			return fmt.Errorf("pattern %q: %v", rule.Pattern, err)
+		}
+		if rule.Mode&^os.ModePerm != 0 || rule.DirMode&^os.ModePerm != 0 {
+			return fmt.Errorf("pattern %q: modes may only contain permission bits", rule.Pattern)
+		}
+	}
+
+	if p.Umask&^os.ModePerm != 0 {
+		return fmt.Errorf("umask may only contain permission bits")
+	}
+
+	return nil
+}
+
+func (rule *PolicyRule) matches(name string) bool {
+	for name != "." && name != "/" {
+		if matched, _ := path.Match(rule.Pattern, name); matched {
+			return true
+		}
+		name = path.Dir(name)
+	}
+	return false
+}
+
+// A nil Policy applies the process umask and sets no owner.
+func (p *Policy) attributes(name string, perm os.FileMode, dir bool) (*Owner, os.FileMode) {
+	if p == nil {
+		return nil, perm &^ os.FileMode(umask)
+	}
+
+	var owner *Owner
+	mode := perm &^ p.Umask
+
+	name = strings.TrimPrefix(path.Clean(name), "/")
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if !rule.matches(name) {
+			continue
+		}
+		if rule.Owner != nil {
+			owner = rule.Owner
+		}
+		if dir && rule.DirMode != 0 {
+			mode = rule.DirMode
+		}
+		if !dir && rule.Mode != 0 {
+			mode = rule.Mode
+		}
+	}
+
+	return owner, mode
+}
+
+func (p *Policy) archiveAttributes(name string, perm os.FileMode, dir bool) (Owner, os.FileMode) {
+	owner, mode := p.attributes(name, perm, dir)
+	if owner == nil {
+		return rootOwner, mode
+	}
+	return *owner, mode
+}
+
+type policyFilesystem interface {
+	setPolicy(policy *Policy)
+}
+
+type withPolicy struct {
+	policy *Policy
+}
+
+func (w *withPolicy) setPolicy(policy *Policy) {
+	w.policy = policy
+}
+
+func SetPolicy(fs Filesystem, policy *Policy) error {
+	err := policy.Validate()
+	if err != nil {
+		return err
+	}
+
+	p, ok := fs.(policyFilesystem)
+	if !ok {
+		return fmt.Errorf("%T does not support ownership policies", fs)
+	}
+	p.setPolicy(policy)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package filesystem
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestPolicyAttributes(t *testing.T) {
+	etcd := &Owner{UID: 997, GID: 997, User: "etcd", Group: "etcd"}
+	root := &Owner{UID: 0, GID: 0}
+
+	p := &Policy{
+		Umask: 0027,
+		Rules: []PolicyRule{
+			{Pattern: "etc/etcd", Owner: etcd, Mode: 0600, DirMode: 0700},
+			{Pattern: "etc/etcd/*.conf", Owner: root, Mode: 0644},
+			{Pattern: "etc/origin/*/*.key", Mode: 0400},
+		},
+	}
+
+	for _, tt := range []struct {
+		name      string
+		perm      os.FileMode
+		dir       bool
+		wantOwner *Owner
+		wantMode  os.FileMode
+	}{
+		{name: "etc", perm: 0777, dir: true, wantMode: 0750},
+		{name: "etc/etcd", perm: 0777, dir: true, wantOwner: etcd, wantMode: 0700},
+		{name: "etc/etcd/ca.crt", perm: 0666, wantOwner: etcd, wantMode: 0600},
+		{name: "etc/etcd/etcd.conf", perm: 0666, wantOwner: root, wantMode: 0644},
+		{name: "/etc/etcd/etcd.conf", perm: 0666, wantOwner: root, wantMode: 0644},
+		{name: "etc/origin/master/ca.key", perm: 0600, wantMode: 0400},
+		{name: "etc/origin/master/ca.crt", perm: 0666, wantMode: 0640},
+		{name: "etc/origin/master/sub/ca.key", perm: 0600, wantMode: 0600},
+	} {
+		owner, mode := p.attributes(tt.name, tt.perm, tt.dir)
+		if !reflect.DeepEqual(owner, tt.wantOwner) || mode != tt.wantMode {
+			t.Errorf("%s: got %v %o, want %v %o", tt.name, owner, mode, tt.wantOwner, tt.wantMode)
+		}
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	for _, p := range []*Policy{
+		{Rules: []PolicyRule{{Pattern: "["}}},
+		{Rules: []PolicyRule{{Pattern: "etc", Mode: os.ModeSetuid | 0755}}},
+		{Umask: os.ModeDir},
+	} {
+		if p.Validate() == nil {
+			t.Errorf("%+v: expected an error", p)
+		}
+	}
+}
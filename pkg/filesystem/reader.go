@@ -0,0 +1,350 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Reader reads back a tree written through a Filesystem.
+type Reader interface {
+	// Files omits a directory's manifest.
+	Files() ([]string, error)
+	ReadFile(filename string) ([]byte, error)
+}
+
+// The format of an archive is determined by its suffix.
+func Open(name string) (Reader, error) {
+	var newReader func(io.Reader) (Reader, error)
+
+	switch {
+	case strings.HasSuffix(name, ".tgz"), strings.HasSuffix(name, ".tar.gz"):
+		newReader = NewTGZFileReader
+	case strings.HasSuffix(name, ".zip"):
+		newReader = NewZipFileReader
+	case strings.HasSuffix(name, ".cpio"):
+		newReader = NewCPIOFileReader
+	case strings.HasSuffix(name, ".yaml"):
+		newReader = NewCloudInitFileReader
+	case strings.HasSuffix(name, ".ign"):
+		newReader = NewIgnitionFileReader
+	default:
+		return NewFilesystemReader(name)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return newReader(f)
+}
+
+type filesystemReader struct {
+	name string
+}
+
+var _ Reader = &filesystemReader{}
+
+func NewFilesystemReader(name string) (Reader, error) {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, &os.PathError{Op: "open", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	return &filesystemReader{name}, nil
+}
+
+func (f *filesystemReader) Files() ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(f.name, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(f.name, path)
+		if err != nil {
+			return err
+		}
+		if rel == ManifestName {
+			return nil
+		}
+		files = append(files, filepath.ToSlash(rel))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+func (f *filesystemReader) ReadFile(filename string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(f.name, filepath.FromSlash(filename)))
+}
+
+func NewTGZFileReader(r io.Reader) (Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	t := NewMemoryFilesystem()
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		b, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		err = t.WriteFile(strings.TrimPrefix(h.Name, "./"), b, h.FileInfo().Mode().Perm())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func NewZipFileReader(r io.Reader) (Reader, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewMemoryFilesystem()
+
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		err = t.WriteFile(strings.TrimPrefix(f.Name, "./"), b, f.Mode().Perm())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func NewCPIOFileReader(r io.Reader) (Reader, error) {
+	t := NewMemoryFilesystem()
+
+	br := bufio.NewReader(r)
+	for {
+		h := make([]byte, 110)
+		_, err := io.ReadFull(br, h)
+		if err != nil {
+			return nil, err
+		}
+		if string(h[:6]) != "070701" {
+			return nil, fmt.Errorf("not a newc format cpio archive")
+		}
+
+		var fields [13]uint32
+		for i := range fields {
+			n, err := strconv.ParseUint(string(h[6+8*i:14+8*i]), 16, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cpio header: %v", err)
+			}
+			fields[i] = uint32(n)
+		}
+		mode, size, namesize := fields[1], int(fields[6]), int(fields[11])
+
+		name := make([]byte, namesize+cpioPad(110+namesize))
+		_, err = io.ReadFull(br, name)
+		if err != nil {
+			return nil, err
+		}
+		filename := strings.TrimRight(string(name[:namesize]), "\x00")
+		if filename == cpioTrailer {
+			break
+		}
+
+		data := make([]byte, size+cpioPad(size))
+		_, err = io.ReadFull(br, data)
+		if err != nil {
+			return nil, err
+		}
+
+		if mode&0170000 == cpioModeReg {
+			err = t.WriteFile(strings.TrimPrefix(filename, "./"), data[:size], os.FileMode(mode).Perm())
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return t, nil
+}
+
+func NewCloudInitFileReader(r io.Reader) (Reader, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(b, []byte(cloudConfigHeader)) {
+		return nil, fmt.Errorf("not a cloud-config document")
+	}
+
+	var config cloudConfig
+	err = yaml.Unmarshal(b, &config)
+	if err != nil {
+		return nil, err
+	}
+
+	t := NewMemoryFilesystem()
+
+	for _, file := range config.WriteFiles {
+		var data []byte
+
+		switch file.Encoding {
+		case "", "text/plain":
+			data = []byte(file.Content)
+
+		case "b64", "base64", "gz+b64", "gzip+base64":
+			data, err = base64.StdEncoding.DecodeString(file.Content)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", file.Path, err)
+			}
+
+			if strings.HasPrefix(file.Encoding, "gz") {
+				gz, err := gzip.NewReader(bytes.NewReader(data))
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", file.Path, err)
+				}
+
+				data, err = ioutil.ReadAll(gz)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %v", file.Path, err)
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("%s: unsupported encoding %q", file.Path, file.Encoding)
+		}
+
+		perm := uint64(0644)
+		if file.Permissions != "" {
+			perm, err = strconv.ParseUint(file.Permissions, 8, 32)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid permissions %q", file.Path, file.Permissions)
+			}
+		}
+
+		err = t.WriteFile(strings.TrimPrefix(file.Path, "/"), data, os.FileMode(perm).Perm())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Only data URL contents are supported.
+func NewIgnitionFileReader(r io.Reader) (Reader, error) {
+	var config ignitionConfig
+	err := json.NewDecoder(r).Decode(&config)
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.Ignition.Version {
+	case IgnitionV2, IgnitionV3:
+	default:
+		return nil, fmt.Errorf("unsupported Ignition spec version %q", config.Ignition.Version)
+	}
+
+	t := NewMemoryFilesystem()
+
+	for _, file := range config.Storage.Files {
+		data, err := parseDataURL(file.Contents.Source)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file.Path, err)
+		}
+
+		err = t.WriteFile(strings.TrimPrefix(file.Path, "/"), data, os.FileMode(file.Mode).Perm())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+func parseDataURL(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "data:") {
+		return nil, fmt.Errorf("unsupported contents source %q", source)
+	}
+
+	i := strings.IndexByte(source, ',')
+	if i == -1 {
+		return nil, fmt.Errorf("invalid data URL")
+	}
+	mediatype, data := source[len("data:"):i], source[i+1:]
+
+	if strings.HasSuffix(mediatype, ";base64") {
+		return base64.StdEncoding.DecodeString(data)
+	}
+
+	s, err := url.PathUnescape(data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(s), nil
+}
@@ -0,0 +1,153 @@
+package filesystem
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testFiles have contents of every length modulo four, to exercise archive
+// padding.
+var testFiles = []struct {
+	filename string
+	data     string
+	perm     os.FileMode
+	mode     os.FileMode
+}{
+	{"a", "", 0666, 0644},
+	{"etc/origin/master/ca.crt", "1", 0666, 0644},
+	{"etc/origin/master/ca.key", "12", 0600, 0600},
+	{"etc/origin/node/node-config.yaml", "123", 0666, 0644},
+	{"etc/etcd/etcd.conf", "1234\n", 0644, 0644},
+}
+
+func writeTestFiles(t *testing.T, fs Filesystem) {
+	err := SetPolicy(fs, &Policy{Umask: 0022})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, f := range testFiles {
+		err = fs.WriteFile(f.filename, []byte(f.data), f.perm)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = fs.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkTestFiles(t *testing.T, r Reader) {
+	files, err := r.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var want []string
+	for _, f := range testFiles {
+		want = append(want, f.filename)
+	}
+	sort.Strings(want)
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("Files() = %v, want %v", files, want)
+	}
+
+	for _, f := range testFiles {
+		b, err := r.ReadFile(f.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != f.data {
+			t.Errorf("%s: read %q, want %q", f.filename, b, f.data)
+		}
+
+		if m, ok := r.(*MemoryFilesystem); ok {
+			info, err := m.Stat(f.filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode() != f.mode {
+				t.Errorf("%s: mode %o, want %o", f.filename, info.Mode(), f.mode)
+			}
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		newFilesystem func(io.Writer) (Filesystem, error)
+	}{
+		{
+			name:          "node.tgz",
+			newFilesystem: NewTGZFile,
+		},
+		{
+			name:          "node.zip",
+			newFilesystem: NewZipFile,
+		},
+		{
+			name:          "node.cpio",
+			newFilesystem: NewCPIOFile,
+		},
+		{
+			name: "node.yaml",
+			newFilesystem: func(w io.Writer) (Filesystem, error) {
+				return NewCloudInitFile(w, false)
+			},
+		},
+		{
+			name: "gzip/node.yaml",
+			newFilesystem: func(w io.Writer) (Filesystem, error) {
+				return NewCloudInitFile(w, true)
+			},
+		},
+		{
+			name: "v2/node.ign",
+			newFilesystem: func(w io.Writer) (Filesystem, error) {
+				return NewIgnitionFile(w, IgnitionV2)
+			},
+		},
+		{
+			name: "v3/node.ign",
+			newFilesystem: func(w io.Writer) (Filesystem, error) {
+				return NewIgnitionFile(w, IgnitionV3)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "certgen-test-")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			name := filepath.Join(dir, filepath.Base(tt.name))
+
+			f, err := os.Create(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			fs, err := tt.newFilesystem(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			writeTestFiles(t, fs)
+
+			r, err := Open(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkTestFiles(t, r)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package filesystem
+
+import (
+	"compress/gzip"
+	"os"
+	"sort"
+	"time"
+)
+
+// ReproducibleModTime is the earliest time a zip file can represent.
+var ReproducibleModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type reproducibleArchive interface {
+	reproducible(modTime time.Time)
+}
+
+type reproduciblefile struct {
+	fs    Filesystem
+	files map[string]reproducibleEntry
+}
+
+type reproducibleEntry struct {
+	data []byte
+	perm os.FileMode
+}
+
+var _ Filesystem = &reproduciblefile{}
+
+// Files are held until Close, then written to fs in sorted order.  Archive
+// entries are stamped with modTime and owners are recorded numerically.
+func NewReproducible(fs Filesystem, modTime time.Time) (Filesystem, error) {
+	if r, ok := fs.(reproducibleArchive); ok {
+		r.reproducible(modTime)
+	}
+
+	return &reproduciblefile{
+		fs:    fs,
+		files: map[string]reproducibleEntry{},
+	}, nil
+}
+
+func (r *reproduciblefile) setPolicy(policy *Policy) {
+	if p, ok := r.fs.(policyFilesystem); ok {
+		p.setPolicy(policy)
+	}
+}
+
+func (r *reproduciblefile) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	r.files[filename] = reproducibleEntry{data: append([]byte{}, data...), perm: perm}
+	return nil
+}
+
+func (r *reproduciblefile) Close() error {
+	filenames := make([]string, 0, len(r.files))
+	for filename := range r.files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	for _, filename := range filenames {
+		err := r.fs.WriteFile(filename, r.files[filename].data, r.files[filename].perm)
+		if err != nil {
+			r.fs.Close()
+			return err
+		}
+	}
+
+	return r.fs.Close()
+}
+
+func (t *tgzfile) reproducible(modTime time.Time) {
+	t.gz.Header = gzip.Header{OS: 255}
+	t.now = modTime
+	t.numericOwner = true
+}
+
+func (z *zipfile) reproducible(modTime time.Time) {
+	z.now = modTime.UTC()
+}
+
+func (c *cpiofile) reproducible(modTime time.Time) {
+	c.now = modTime
+}
@@ -0,0 +1,122 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestReproducible(t *testing.T) {
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name          string
+		newFilesystem func(io.Writer) (Filesystem, error)
+	}{
+		{"tgz", NewTGZFile},
+		{"zip", NewZipFile},
+		{"cpio", NewCPIOFile},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var archives [2][]byte
+			for i := range archives {
+				buf := &bytes.Buffer{}
+
+				fs, err := tt.newFilesystem(buf)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				fs, err = NewReproducible(fs, modTime)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// the order in which files are written must not matter
+				for j := range testFiles {
+					f := testFiles[j]
+					if i == 1 {
+						f = testFiles[len(testFiles)-1-j]
+					}
+					err = fs.WriteFile(f.filename, []byte(f.data), f.perm)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+
+				err = fs.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				archives[i] = buf.Bytes()
+			}
+
+			if !bytes.Equal(archives[0], archives[1]) {
+				t.Error("archives differ")
+			}
+		})
+	}
+}
+
+func TestReproducibleTGZ(t *testing.T) {
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	buf := &bytes.Buffer{}
+
+	fs, err := NewTGZFile(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fs, err = NewReproducible(fs, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SetPolicy(fs, &Policy{Rules: []PolicyRule{{Pattern: "etc", Owner: &Owner{UID: 997, GID: 997, User: "etcd", Group: "etcd"}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fs.WriteFile("etc/etcd/etcd.conf", nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fs.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the gzip header's MTIME is unset and its OS is "unknown"
+	b := buf.Bytes()
+	if !bytes.Equal(b[4:8], []byte{0, 0, 0, 0}) || b[9] != 255 {
+		t.Errorf("gzip header %x is not reproducible", b[:10])
+	}
+
+	gz, err := gzip.NewReader(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !h.ModTime.Equal(modTime) {
+			t.Errorf("%s: ModTime %s, want %s", h.Name, h.ModTime, modTime)
+		}
+		if h.Uid != 997 || h.Gid != 997 || h.Uname != "" || h.Gname != "" {
+			t.Errorf("%s: owner %d:%d (%q:%q), want 997:997 only", h.Name, h.Uid, h.Gid, h.Uname, h.Gname)
+		}
+	}
+}
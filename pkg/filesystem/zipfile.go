@@ -0,0 +1,84 @@
+package filesystem
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+type zipfile struct {
+	withPolicy
+	zw   *zip.Writer
+	now  time.Time
+	dirs map[string]struct{}
+}
+
+var _ Filesystem = &zipfile{}
+
+// Entries' owners are recorded in an Info-ZIP Unix extra field.
+func NewZipFile(w io.Writer) (Filesystem, error) {
+	return &zipfile{
+		zw:   zip.NewWriter(w),
+		now:  time.Now(),
+		dirs: map[string]struct{}{},
+	}, nil
+}
+
+// zipOwnerExtra returns an Info-ZIP "new Unix" extra field (0x7875).
+func zipOwnerExtra(owner Owner) []byte {
+	b := []byte{0x75, 0x78, 11, 0, 1, 4, 0, 0, 0, 0, 4, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(b[6:], uint32(owner.UID))
+	binary.LittleEndian.PutUint32(b[11:], uint32(owner.GID))
+	return b
+}
+
+func (z *zipfile) header(name string, perm os.FileMode, dir bool) *zip.FileHeader {
+	owner, mode := z.policy.archiveAttributes(name, perm, dir)
+
+	h := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: z.now,
+		Extra:    zipOwnerExtra(owner),
+	}
+	if dir {
+		h.Name += "/"
+		h.Method = zip.Store
+		mode |= os.ModeDir
+	}
+	h.SetMode(mode)
+
+	return h
+}
+
+func (z *zipfile) mkdirAll(name string, perm os.FileMode) error {
+	for _, name := range missingDirs(z.dirs, name) {
+		_, err := z.zw.CreateHeader(z.header(name, perm, true))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (z *zipfile) WriteFile(filename string, data []byte, perm os.FileMode) error {
+	err := z.mkdirAll(path.Dir(filename), 0777)
+	if err != nil {
+		return err
+	}
+
+	w, err := z.zw.CreateHeader(z.header(filename, perm, false))
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(data)
+	return err
+}
+
+func (z *zipfile) Close() error {
+	return z.zw.Close()
+}
@@ -0,0 +1,62 @@
+package filesystem
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestZipOwnerExtra(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	fs, err := NewZipFile(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = SetPolicy(fs, &Policy{Rules: []PolicyRule{{Pattern: "etc/etcd", Owner: &Owner{UID: 997, GID: 70000}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fs.WriteFile("etc/etcd/etcd.conf", nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = fs.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string][2]uint32{
+		"etc/":               {0, 0},
+		"etc/etcd/":          {997, 70000},
+		"etc/etcd/etcd.conf": {997, 70000},
+	}
+	for _, f := range zr.File {
+		// tag, size, version, UID size, UID, GID size, GID; archive/zip
+		// appends an extended timestamp field
+		extra := f.Extra
+		if len(extra) < 15 || binary.LittleEndian.Uint16(extra) != 0x7875 || binary.LittleEndian.Uint16(extra[2:]) != 11 ||
+			extra[4] != 1 || extra[5] != 4 || extra[10] != 4 {
+			t.Errorf("%s: invalid extra field %x", f.Name, extra)
+			continue
+		}
+
+		got := [2]uint32{binary.LittleEndian.Uint32(extra[6:]), binary.LittleEndian.Uint32(extra[11:])}
+		if got != want[f.Name] {
+			t.Errorf("%s: owner %v, want %v", f.Name, got, want[f.Name])
+		}
+		delete(want, f.Name)
+	}
+	for name := range want {
+		t.Errorf("%s: missing", name)
+	}
+}